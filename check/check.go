@@ -7,58 +7,83 @@ import (
 	"errors"
 	"fmt"
 	"github.com/michaeljpetter/command/value"
+	"net/netip"
+	"net/url"
 	"slices"
 	"strings"
+	"time"
 )
 
 // GreaterThan checks that a value is greater than a given minimum.
 func GreaterThan[T cmp.Ordered](min T) value.CheckFunc[T] {
-	return func(value T) error {
+	return value.Describe(func(value T) error {
 		if min < value {
 			return nil
 		}
 		return fmt.Errorf("must be greater than %v", min)
-	}
+	}, value.Constraint{
+		Description:  fmt.Sprintf("greater than %v", min),
+		Min:          fmt.Sprint(min),
+		MinExclusive: true,
+	})
 }
 
 // LessThan checks that a value is less than a given maximum.
 func LessThan[T cmp.Ordered](max T) value.CheckFunc[T] {
-	return func(value T) error {
+	return value.Describe(func(value T) error {
 		if value < max {
 			return nil
 		}
 		return fmt.Errorf("must be less than %v", max)
-	}
+	}, value.Constraint{
+		Description:  fmt.Sprintf("less than %v", max),
+		Max:          fmt.Sprint(max),
+		MaxExclusive: true,
+	})
 }
 
 // AtLeast checks that a value is greater than or equal to a given minimum.
 func AtLeast[T cmp.Ordered](min T) value.CheckFunc[T] {
-	return func(value T) error {
+	return value.Describe(func(value T) error {
 		if min <= value {
 			return nil
 		}
 		return fmt.Errorf("must be at least %v", min)
-	}
+	}, value.Constraint{
+		Description: fmt.Sprintf("at least %v", min),
+		Min:         fmt.Sprint(min),
+	})
 }
 
 // AtMost checks that a value is less than or equal to a given maximum.
 func AtMost[T cmp.Ordered](max T) value.CheckFunc[T] {
-	return func(value T) error {
+	return value.Describe(func(value T) error {
 		if value <= max {
 			return nil
 		}
 		return fmt.Errorf("must be at most %v", max)
-	}
+	}, value.Constraint{
+		Description: fmt.Sprintf("at most %v", max),
+		Max:         fmt.Sprint(max),
+	})
 }
 
 // OneOf checks that a value is present in a given list of allowed options.
 func OneOf[T comparable](options ...T) value.CheckFunc[T] {
-	return func(value T) error {
+	choices := make([]string, 0, len(options))
+	for _, option := range options {
+		choices = append(choices, fmt.Sprint(option))
+	}
+
+	return value.Describe(func(value T) error {
 		if slices.Contains(options, value) {
 			return nil
 		}
 		return fmt.Errorf("must be one of %v", options)
-	}
+	}, value.Constraint{
+		Description: "one of " + strings.Join(choices, ", "),
+		Choices:     choices,
+	})
 }
 
 // NotBlank checks that a string contains at least one non-white-space character.
@@ -68,3 +93,91 @@ func NotBlank(value string) error {
 	}
 	return errors.New("cannot be blank")
 }
+
+// Before checks that a time is before a given limit.
+func Before(limit time.Time) value.CheckFunc[time.Time] {
+	return value.Describe(func(value time.Time) error {
+		if value.Before(limit) {
+			return nil
+		}
+		return fmt.Errorf("must be before %v", limit.Format(time.RFC3339))
+	}, value.Constraint{
+		Description:  "before " + limit.Format(time.RFC3339),
+		Max:          limit.Format(time.RFC3339),
+		MaxExclusive: true,
+	})
+}
+
+// After checks that a time is after a given limit.
+func After(limit time.Time) value.CheckFunc[time.Time] {
+	return value.Describe(func(value time.Time) error {
+		if value.After(limit) {
+			return nil
+		}
+		return fmt.Errorf("must be after %v", limit.Format(time.RFC3339))
+	}, value.Constraint{
+		Description:  "after " + limit.Format(time.RFC3339),
+		Min:          limit.Format(time.RFC3339),
+		MinExclusive: true,
+	})
+}
+
+// Scheme checks that a URL has one of a given list of allowed schemes, ignoring case.
+func Scheme(schemes ...string) value.CheckFunc[*url.URL] {
+	return value.Describe(func(value *url.URL) error {
+		if slices.ContainsFunc(schemes, func(scheme string) bool { return strings.EqualFold(scheme, value.Scheme) }) {
+			return nil
+		}
+		return fmt.Errorf("scheme must be one of %v", schemes)
+	}, value.Constraint{
+		Description: "scheme one of " + strings.Join(schemes, ", "),
+	})
+}
+
+// IsIPv4 checks that an address is an IPv4 address, including IPv4-mapped IPv6 addresses.
+func IsIPv4(value netip.Addr) error {
+	if value.Unmap().Is4() {
+		return nil
+	}
+	return errors.New("must be an IPv4 address")
+}
+
+// IsIPv6 checks that an address is an IPv6 address, excluding IPv4-mapped IPv6 addresses.
+func IsIPv6(value netip.Addr) error {
+	if value.Is6() && !value.Is4In6() {
+		return nil
+	}
+	return errors.New("must be an IPv6 address")
+}
+
+// IsPrivate checks that an address is a private address, as by [netip.Addr.IsPrivate].
+func IsPrivate(value netip.Addr) error {
+	if value.Unmap().IsPrivate() {
+		return nil
+	}
+	return errors.New("must be a private address")
+}
+
+// InPrefix checks that an address is contained in at least one of a given list of prefixes.
+func InPrefix(prefixes ...netip.Prefix) value.CheckFunc[netip.Addr] {
+	names := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		names = append(names, prefix.String())
+	}
+
+	return value.Describe(func(value netip.Addr) error {
+		if slices.ContainsFunc(prefixes, func(prefix netip.Prefix) bool { return prefix.Contains(value.Unmap()) }) {
+			return nil
+		}
+		return fmt.Errorf("must be in one of %v", prefixes)
+	}, value.Constraint{
+		Description: "in " + strings.Join(names, ", "),
+	})
+}
+
+func init() {
+	value.Describe(NotBlank, value.Constraint{Description: "not blank"})
+	value.Describe(IsIPv4, value.Constraint{Description: "IPv4"})
+	value.Describe(IsIPv6, value.Constraint{Description: "IPv6"})
+	value.Describe(IsPrivate, value.Constraint{Description: "private"})
+}
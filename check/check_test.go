@@ -2,7 +2,12 @@ package check_test
 
 import (
 	"github.com/michaeljpetter/command/check"
+	"github.com/michaeljpetter/command/value"
+	"net/netip"
+	"net/url"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestGreaterThan(t *testing.T) {
@@ -70,3 +75,114 @@ func TestNotBlank(t *testing.T) {
 		t.Error("did not fail with invalid value")
 	}
 }
+
+func TestBefore(t *testing.T) {
+	limit := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	check := check.Before(limit)
+
+	if check(limit.Add(-time.Second)) != nil {
+		t.Error("did not pass with valid value")
+	}
+	if check(limit) == nil {
+		t.Error("did not fail with invalid value")
+	}
+}
+
+func TestAfter(t *testing.T) {
+	limit := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	check := check.After(limit)
+
+	if check(limit.Add(time.Second)) != nil {
+		t.Error("did not pass with valid value")
+	}
+	if check(limit) == nil {
+		t.Error("did not fail with invalid value")
+	}
+}
+
+func TestScheme(t *testing.T) {
+	check := check.Scheme("http", "https")
+
+	if check(&url.URL{Scheme: "HTTPS"}) != nil {
+		t.Error("did not pass with valid value")
+	}
+	if check(&url.URL{Scheme: "ftp"}) == nil {
+		t.Error("did not fail with invalid value")
+	}
+}
+
+func TestIsIPv4(t *testing.T) {
+	check := check.IsIPv4
+
+	if check(netip.MustParseAddr("::ffff:10.0.0.1")) != nil {
+		t.Error("did not pass with valid value")
+	}
+	if check(netip.MustParseAddr("fe80::1")) == nil {
+		t.Error("did not fail with invalid value")
+	}
+}
+
+func TestIsIPv6(t *testing.T) {
+	check := check.IsIPv6
+
+	if check(netip.MustParseAddr("fe80::1")) != nil {
+		t.Error("did not pass with valid value")
+	}
+	if check(netip.MustParseAddr("::ffff:10.0.0.1")) == nil {
+		t.Error("did not fail with invalid value")
+	}
+}
+
+func TestIsPrivate(t *testing.T) {
+	check := check.IsPrivate
+
+	if check(netip.MustParseAddr("192.168.1.1")) != nil {
+		t.Error("did not pass with valid value")
+	}
+	if check(netip.MustParseAddr("8.8.8.8")) == nil {
+		t.Error("did not fail with invalid value")
+	}
+}
+
+func TestInPrefix(t *testing.T) {
+	check := check.InPrefix(netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8"))
+
+	if check(netip.MustParseAddr("fd12::1")) != nil {
+		t.Error("did not pass with valid value")
+	}
+	if check(netip.MustParseAddr("11.0.0.1")) == nil {
+		t.Error("did not fail with invalid value")
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	port := value.Constraints(check.AtLeast(1), check.AtMost(65535))
+
+	if len(port) != 2 || port[0].Min != "1" || port[1].Max != "65535" {
+		t.Errorf("wrong constraints %+v", port)
+	}
+	if description := value.DescribeConstraints(port); description != "1..65535" {
+		t.Errorf("wrong description %v", description)
+	}
+
+	mixed := value.Constraints(check.GreaterThan(0), check.OneOf(1, 2), check.AtLeast(5), check.AtLeast(6))
+
+	if description := value.DescribeConstraints(mixed); description != "greater than 0, one of 1, 2, at least 5, at least 6" {
+		t.Errorf("wrong description %v", description)
+	}
+	if !slices.Equal(mixed[1].Choices, []string{"1", "2"}) {
+		t.Errorf("wrong choices %v", mixed[1].Choices)
+	}
+
+	blank := value.Constraints(check.NotBlank, func(string) error { return nil })
+
+	if description := value.DescribeConstraints(blank); description != "not blank" {
+		t.Errorf("wrong description %v", description)
+	}
+
+	addr := value.Constraints(check.IsIPv4, check.IsPrivate, check.InPrefix(netip.MustParsePrefix("10.0.0.0/8")))
+
+	if description := value.DescribeConstraints(addr); description != "IPv4, private, in 10.0.0.0/8" {
+		t.Errorf("wrong description %v", description)
+	}
+}
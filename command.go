@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"github.com/michaeljpetter/command/flag"
 	"github.com/michaeljpetter/fp"
+	"io"
 	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strings"
+	"text/template"
 )
 
 // Command represents a single command in a command tree, which may
@@ -23,11 +26,65 @@ type Command struct {
 	usage       string
 	subcommands map[string]subcommand
 	positional  []*positional
+	version     *versionValue
+	examples    []Example
+	sections    []Section
+	validators  []ValidatorFunc
+	hooks       hooks
+	allHooks    hooks
+	inherited   hooks // allHooks of all ancestors
+	around      hooks // hooks of the parent
+	pending     hooks // hooks to run around this command
+	ran         bool  // whether the before hooks have run
+	middleware  []Middleware
+	failure     error // reported by middleware
+	shell       *Shell
+	session     *session
+	abort       bool // whether failures abort the handler within a shell
+	hidden      bool // whether the parent hides this command
 
 	// The behavior of Usage is analogous to FlagSet, but it extended by default to
 	// display usage information for all flags, subcommands, and positional parameters.
 	// Usage may be replaced with a user function to customize output.
 	Usage func()
+
+	// UsageTemplate is the template used by default to render usage, in place of
+	// the package-level [UsageTemplate], and may be used to customize individual sections.
+	// It is inherited by subcommands.
+	UsageTemplate *template.Template
+
+	// Terminal is used to prompt for missing required secrets when it is interactive,
+	// and for any other missing required positional parameters when Prompt is set.
+	// It defaults to [StdTerminal], is inherited by subcommands, and may be replaced,
+	// such as for testing.
+	Terminal Terminal
+
+	// Prompt enables prompting for missing required positional parameters when the Terminal
+	// is interactive, repeating the prompt until a valid value is given.
+	// It is inherited by subcommands.
+	Prompt bool
+
+	// Help enables a built-in help subcommand on commands which define subcommands,
+	// such that "help a b" prints the usage of subcommand "a b" as it is declared by [Command.Define],
+	// without calling any handler, and reports [flag.ErrHelp].
+	// It is inherited by subcommands, and is overridden by any explicitly defined help subcommand.
+	Help bool
+
+	// Plugins enables external subcommands on commands which define subcommands, such that an unknown
+	// subcommand "foo" of command "prog cluster" runs the executable "prog-cluster-foo", if found,
+	// with the remaining arguments. On Windows, the executable is found by any extension listed by PATHEXT,
+	// which is omitted from the plugin name. Plugins are listed in usage under a separate heading.
+	//
+	// The plugin is run with the variables COMMAND_NAME, COMMAND_PLUGIN, and COMMAND_EXECUTABLE added
+	// to its environment, giving the name of this command, the name of the plugin, and the path of the
+	// running executable. Its errors are written to the output of the command, and its input and output
+	// are those of the shell when run from one. Should it fail, its exit status is used under [flag.ExitOnError].
+	// It is inherited by subcommands.
+	Plugins bool
+
+	// PluginDirs lists the directories searched for plugins, in order, in place of those in PATH.
+	// It is inherited by subcommands.
+	PluginDirs []string
 }
 
 // Bound represents a [Command] that has been paired with a specific set
@@ -43,15 +100,41 @@ type Bound struct {
 // It is used during parsing to delegate to subcommands.
 type HandlerFunc func(Bound)
 
+// DefineFunc defines a function that declares the flags, subcommands, and positional parameters
+// of a subcommand, separately from its [HandlerFunc].
+type DefineFunc func(*Command)
+
+// ValidatorFunc defines a function that validates a [Command] once all of its flags and
+// positional parameters have been set, such as to enforce rules spanning several values.
+type ValidatorFunc func(*Command) error
+
 // Value extends the [flag.Value] type, adding support for required values.
 type Value interface {
 	flag.Value
 	Required() bool
 }
 
+// Example describes an example invocation of a command.
+type Example struct {
+	// Command is the example command line.
+	Command string
+
+	// Usage describes what the example does, and may be empty.
+	Usage string
+}
+
+// Section describes an additional titled section of command help,
+// such as "Environment", "Exit Status", or "See Also".
+type Section struct {
+	Title string
+	Text  string
+}
+
 type subcommand struct {
 	usage   string
+	define  DefineFunc
 	handler HandlerFunc
+	hidden  bool
 }
 
 type positional struct {
@@ -59,6 +142,7 @@ type positional struct {
 	usage    string
 	value    Value
 	defValue string
+	set      bool
 }
 
 // New creates a new [Command] with the given name, usage, and error handling.
@@ -72,6 +156,7 @@ func New(name, usage string, errorHandling flag.ErrorHandling) *Command {
 
 	c.FlagSet.Usage = c.delegateUsage
 	c.Usage = c.defaultUsage
+	c.Terminal = StdTerminal()
 
 	return c
 }
@@ -83,11 +168,38 @@ func New(name, usage string, errorHandling flag.ErrorHandling) *Command {
 // Panics if positional parameters have been defined on the same command,
 // as they are mutually exclusive.
 func (c *Command) Subcommand(name, usage string, handler HandlerFunc) {
+	c.Define(name, usage, nil, handler)
+}
+
+// Define defines a subcommand in the same way as [Command.Subcommand], with its flags, subcommands,
+// and positional parameters declared by define, which is called on the subcommand before its handler.
+// If handler is nil, the subcommand is simply parsed.
+//
+// Unlike a handler, define is also called whenever the subcommand is described, such as by
+// [Command.Walk], the help subcommand, and [Command.Complete], so it must do nothing else.
+func (c *Command) Define(name, usage string, define DefineFunc, handler HandlerFunc) {
 	if c.HasPositional() {
 		panic("subcommands and positional parameters are mutually exclusive")
 	}
 
-	c.subcommands[name] = subcommand{usage, handler}
+	if handler == nil {
+		handler = func(b Bound) { b.Parse() }
+	}
+
+	c.subcommands[name] = subcommand{usage: usage, define: define, handler: handler}
+}
+
+// HideSubcommand hides the named subcommand from usage, completion, and documentation,
+// though it may still be given.
+//
+// Panics if no such subcommand is defined.
+func (c *Command) HideSubcommand(name string) {
+	subcommand, ok := c.subcommands[name]
+	if !ok {
+		panic(fmt.Sprintf("no such subcommand %s", name))
+	}
+	subcommand.hidden = true
+	c.subcommands[name] = subcommand
 }
 
 // PositionalVar defines a positional parameter with the given [Value], name, and usage.
@@ -106,7 +218,43 @@ func (c *Command) PositionalVar(value Value, name, usage string) {
 		panic("required positional parameters must precede optional")
 	}
 
-	c.positional = append(c.positional, &positional{name, usage, value, value.String()})
+	c.positional = append(c.positional, &positional{name, usage, value, value.String(), false})
+}
+
+// Example adds an example invocation of the command, with a description of what it does.
+// Examples are shown in usage output in the order added, after any subcommands or positional parameters.
+func (c *Command) Example(command, usage string) {
+	c.examples = append(c.examples, Example{command, usage})
+}
+
+// Section adds an additional section of help to the command, with the given title and text.
+// Sections are shown in usage output in the order added, after any examples.
+func (c *Command) Section(title, text string) {
+	c.sections = append(c.sections, Section{title, text})
+}
+
+// Validator adds validators to the command, which are run in the order added after all flags and
+// positional parameters have been parsed successfully, and before any subcommand is run.
+// A validator error is reported in the same way as any other parse error.
+func (c *Command) Validator(validators ...ValidatorFunc) {
+	c.validators = append(c.validators, validators...)
+}
+
+// IsSet indicates whether the flag or positional parameter with the given name
+// was explicitly given, rather than taking its default value.
+func (c *Command) IsSet(name string) bool {
+	set := false
+	c.FlagSet.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	if set {
+		return true
+	}
+
+	for _, positional := range c.positional {
+		if positional.name == name {
+			return positional.set
+		}
+	}
+	return false
 }
 
 // HasFlags indicates whether flags have been defined on this command.
@@ -137,13 +285,38 @@ func (c *Command) delegateUsage() {
 // PrintSubcommands prints, to standard error unless configured otherwise,
 // the list of all defined subcommands and their usage strings.
 func (c *Command) PrintSubcommands() {
-	names := slices.Sorted(maps.Keys(c.subcommands))
+	usages := c.subcommandUsages()
+	names := slices.Sorted(maps.Keys(usages))
 
 	longest := fp.MaxOf(fp.StringLen, 4)(slices.Values(names))
 
 	for _, name := range names {
-		fmt.Fprintf(c.Output(), "  %-*s  %s\n", longest, name, c.subcommands[name].usage)
+		if c.subcommands[name].hidden {
+			continue
+		}
+		fmt.Fprint(c.Output(), formatEntry(c.WrapWidth(), longest, name, usages[name]))
 	}
+
+	c.printPlugins()
+}
+
+// subcommandUsages returns the usage strings of all subcommands,
+// including any enabled built-in subcommands not otherwise defined.
+func (c *Command) subcommandUsages() map[string]string {
+	usages := make(map[string]string, len(c.subcommands)+3)
+	for name, subcommand := range c.subcommands {
+		usages[name] = subcommand.usage
+	}
+	if _, ok := usages[helpCommand]; c.Help && !ok {
+		usages[helpCommand] = helpUsage
+	}
+	if _, ok := usages[versionCommand]; c.version != nil && !ok {
+		usages[versionCommand] = versionUsage
+	}
+	if _, ok := usages[shellCommand]; c.shell != nil && !ok {
+		usages[shellCommand] = shellUsage
+	}
+	return usages
 }
 
 // PrintPositional prints, to standard error unless configured otherwise,
@@ -153,85 +326,99 @@ func (c *Command) PrintPositional() {
 
 	longest := fp.MaxOf(fp.StringLen, 4)(names)
 
-	for _, positional := range c.positional {
-		fmt.Fprintf(c.Output(), "  %-*s  %s", longest, positional.name, positional.usage)
-
-		if !positional.value.Required() {
-			fmt.Fprintf(c.Output(), " (default %s)", positional.defValue)
-		}
-
-		fmt.Fprint(c.Output(), "\n")
+	for _, positional := range c.Describe().Positional {
+		fmt.Fprint(c.Output(), formatEntry(c.WrapWidth(), longest, positional.Name, positional.Description()))
 	}
 }
 
 func (c *Command) defaultUsage() {
-	fmt.Fprintf(c.Output(), "Usage: %s", c.Name())
+	if err := c.RenderUsage(); err != nil {
+		fmt.Fprintln(c.Output(), err)
+	}
+}
 
-	if c.HasFlags() {
-		fmt.Fprint(c.Output(), " [options]")
+// Parse parses the given arguments according to the definition of the command.
+// The behavior on error is defined by the [flag.ErrorHandling] value used to create the command.
+//
+// Requests for help, via -h, -help, or the help subcommand, print usage and are reported
+// as [flag.ErrHelp], which exits with status 0 under [flag.ExitOnError].
+// Requests for version information are reported likewise as [ErrVersion].
+//
+// When CollectErrors is set, parsing continues past any flags and positional arguments which fail,
+// reported as [*flag.FlagError] and [*ArgumentError]. All failures are printed before usage is printed once,
+// and multiple failures are returned joined by [errors.Join].
+func (c *Command) Parse(args []string) error {
+	c.bindStdin()
+
+	var err, flagErr error
+	if !c.CollectErrors {
+		if err = c.FlagSet.Parse(args); err != nil {
+			return c.handle(err)
+		}
+	} else if err = c.FlagSet.ParseAll(args); err != flag.ErrHelp {
+		flagErr, err = err, nil
 	}
 
-	if c.HasSubcommands() {
-		fmt.Fprint(c.Output(), " <command>")
-	} else if c.HasPositional() {
-		for _, positional := range c.positional {
-			if positional.value.Required() {
-				fmt.Fprintf(c.Output(), " <%s>", positional.name)
-			} else {
-				fmt.Fprintf(c.Output(), " [%s]", positional.name)
-			}
+	switch {
+	case err != nil:
+		// help was requested while collecting errors
+	case flagErr == nil && c.version != nil && c.version.requested:
+		err = c.printVersion(c.version.json)
+	case c.HasSubcommands():
+		// a subcommand is never run after its parent fails
+		if flagErr == nil {
+			err = c.validate()
+		}
+		if flagErr == nil && err == nil {
+			err = c.parseCommand(c.FlagSet.Args())
+		}
+	default:
+		if c.HasPositional() {
+			err = c.parsePositional(c.FlagSet.Args())
+		}
+		if flagErr == nil && err == nil {
+			err = c.validate()
 		}
 	}
 
-	fmt.Fprintf(c.Output(), "\n\n")
+	err = joinErrors(flagErr, err)
 
-	for _, line := range strings.Split(c.usage, "\n") {
-		fmt.Fprintf(c.Output(), "  %s\n", line)
-	}
+	if err != nil {
+		if r, ok := err.(reported); ok {
+			err = r.error
+		} else if err != flag.ErrHelp && err != ErrVersion {
+			fmt.Fprintln(c.Output(), err)
+			c.delegateUsage()
+		}
 
-	if c.HasFlags() {
-		fmt.Fprint(c.Output(), "\nOptions:\n")
-		c.FlagSet.PrintDefaults()
+		return c.handle(err)
 	}
 
-	if c.HasSubcommands() {
-		fmt.Fprint(c.Output(), "\nCommands:\n")
-		c.PrintSubcommands()
-	} else if c.HasPositional() {
-		fmt.Fprint(c.Output(), "\nArguments:\n")
-		c.PrintPositional()
-	}
+	return nil
 }
 
-// Parse parses the given arguments according to the definition of the command.
-// The behavior on error is defined by the [flag.ErrorHandling] value used to create the command.
-func (c *Command) Parse(args []string) error {
-	err := c.FlagSet.Parse(args)
-	if err != nil {
-		return err
-	}
-
-	if c.HasSubcommands() {
-		err = c.parseCommand(c.FlagSet.Args())
-	} else if c.HasPositional() {
-		err = c.parsePositional(c.FlagSet.Args())
+// handle applies the error handling of the command to an error which has been reported.
+//
+// Within a shell, a command which would exit or panic instead ends the goroutine running its handler.
+func (c *Command) handle(err error) error {
+	if c.abort {
+		runtime.Goexit()
 	}
 
-	if err != nil {
-		fmt.Fprintln(c.Output(), err)
-		c.delegateUsage()
-
-		switch c.ErrorHandling() {
-		case flag.ContinueOnError:
-			return err
-		case flag.ExitOnError:
-			os.Exit(2)
-		case flag.PanicOnError:
-			panic(err)
+	switch c.ErrorHandling() {
+	case flag.ExitOnError:
+		if err == flag.ErrHelp || err == ErrVersion {
+			os.Exit(0)
+		}
+		var exit interface{ ExitCode() int }
+		if errors.As(err, &exit) && 0 < exit.ExitCode() {
+			os.Exit(exit.ExitCode())
 		}
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
 	}
-
-	return nil
+	return err
 }
 
 func (c *Command) parseCommand(args []string) error {
@@ -242,34 +429,207 @@ func (c *Command) parseCommand(args []string) error {
 	name := strings.TrimSpace(args[0])
 	subcommand, ok := c.subcommands[name]
 
+	if !ok && c.Help && name == helpCommand {
+		return c.parseHelp(args[1:])
+	}
+
+	if !ok && c.version != nil && name == versionCommand {
+		return c.parseVersion(args[1:])
+	}
+
+	if !ok && c.shell != nil && name == shellCommand {
+		return c.parseShell(args[1:])
+	}
+
+	if !ok && c.Plugins {
+		if path, found := c.lookPlugin(name); found {
+			return c.runPlugin(name, path, args[1:])
+		}
+	}
+
 	if !ok {
 		return fmt.Errorf("unknown command: %s", name)
 	}
 
-	subcommand.handler(
-		New(
-			c.Name()+" "+name,
-			subcommand.usage,
-			c.ErrorHandling(),
-		).
-			Bind(args[1:]),
-	)
-	return nil
+	handler := subcommand.handler
+	for _, middleware := range slices.Backward(c.middleware) {
+		handler = middleware(handler)
+	}
+
+	child := c.describeSubcommand(name)
+	if err := child.runBefore(); err != nil {
+		return err
+	}
+	handler(child.Bind(args[1:]))
+
+	if err := child.runAfter(); err != nil || child.failure == nil {
+		return err
+	}
+	return reported{child.failure}
+}
+
+// newChild creates a subcommand of this command, inheriting its settings.
+//
+// Within a shell, subcommands use the input and output of the shell, and continue on error,
+// except that failures abort the handler where they would otherwise exit or panic.
+func (c *Command) newChild(name, usage string, errorHandling flag.ErrorHandling) *Command {
+	abort := false
+	if c.session != nil {
+		abort, errorHandling = errorHandling != flag.ContinueOnError, flag.ContinueOnError
+	}
+
+	child := New(c.Name()+" "+name, usage, errorHandling)
+	child.abort = abort
+	child.Terminal = c.Terminal
+	child.Prompt = c.Prompt
+	child.Help = c.Help
+	child.Wrap = c.Wrap
+	child.Width = c.Width
+	child.UsageTemplate = c.UsageTemplate
+	child.CollectErrors = c.CollectErrors
+	child.Plugins = c.Plugins
+	child.PluginDirs = c.PluginDirs
+	child.inherited = c.inherited.join(c.allHooks)
+	child.around = c.hooks
+	child.middleware = slices.Clone(c.middleware)
+	child.session = c.session
+	if c.session != nil {
+		child.SetOutput(c.session.out)
+		child.Terminal = c.session.terminal
+	}
+	return child
+}
+
+// reported wraps an error which has already been printed along with usage.
+type reported struct {
+	error
+}
+
+const (
+	helpCommand = "help"
+	helpUsage   = "show help for a command"
+)
+
+// parseHelp prints the usage of the subcommand at the given path, as it is declared,
+// without calling any handler or middleware.
+func (c *Command) parseHelp(path []string) error {
+	target := c
+	for _, name := range path {
+		child, ok := target.describe(name)
+		if !ok {
+			return fmt.Errorf("unknown command: %s", name)
+		}
+		target = child
+	}
+
+	target.SetOutput(c.Output())
+	target.delegateUsage()
+	return flag.ErrHelp
+}
+
+// ArgumentError reports a failure to parse a single positional argument.
+type ArgumentError struct {
+	// Name is the name of the positional argument.
+	Name string
+
+	// Err is the error returned by the Set method of the argument value, if any.
+	Err error
+
+	message string
+}
+
+func (e *ArgumentError) Error() string {
+	return e.message
+}
+
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// bindStdin directs any secrets given as - to read the input of the command,
+// which is that of the shell within one, or otherwise standard input.
+func (c *Command) bindStdin() {
+	var stdin io.Reader = os.Stdin
+	if c.session != nil {
+		stdin = c.session.in
+	}
+
+	bind := func(v any) {
+		if secret, ok := v.(interface{ SetStdin(io.Reader) }); ok {
+			secret.SetStdin(stdin)
+		}
+	}
+
+	c.FlagSet.VisitAll(func(f *flag.Flag) { bind(f.Value) })
+	for _, positional := range c.positional {
+		bind(positional.value)
+	}
 }
 
+// parsePositional sets each positional argument, stopping at the first failure,
+// unless CollectErrors is set, in which case all failures are returned joined.
 func (c *Command) parsePositional(args []string) error {
+	var errs []error
 	for i, positional := range c.positional {
 		if len(args) <= i {
-			if positional.value.Required() {
-				return fmt.Errorf("missing argument for <%s>", positional.name)
+			if !positional.value.Required() {
+				continue
+			}
+			prompted, err := c.prompt(positional)
+			if err != nil {
+				return joinErrors(append(errs, err)...)
+			}
+			if prompted {
+				positional.set = true
+			} else {
+				errs = append(errs, &ArgumentError{positional.name, nil,
+					fmt.Sprintf("missing argument for <%s>", positional.name)})
 			}
+		} else if err := positional.value.Set(args[i]); err != nil {
+			errs = append(errs, &ArgumentError{positional.name, err,
+				fmt.Sprintf("invalid value \"%s\" for argument %s: %v", flag.Display(positional.value, args[i]), positional.name, err)})
+		} else {
+			positional.set = true
+		}
+
+		if 0 < len(errs) && !c.CollectErrors {
 			break
 		}
-		if err := positional.value.Set(args[i]); err != nil {
-			return fmt.Errorf("invalid value \"%s\" for argument %s: %v", args[i], positional.name, err)
+	}
+	return joinErrors(errs...)
+}
+
+// validate runs each validator, stopping at the first failure,
+// unless CollectErrors is set, in which case all failures are returned joined.
+func (c *Command) validate() error {
+	var errs []error
+	for _, validator := range c.validators {
+		if err := validator(c); err != nil {
+			errs = append(errs, err)
+			if !c.CollectErrors {
+				break
+			}
+		}
+	}
+	return joinErrors(errs...)
+}
+
+// joinErrors joins errors as by [errors.Join], flattening any which are already joined,
+// and returning a single error as it is.
+func joinErrors(errs ...error) error {
+	var flat []error
+	for _, err := range errs {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			flat = append(flat, joined.Unwrap()...)
+		} else if err != nil {
+			flat = append(flat, err)
 		}
 	}
-	return nil
+
+	if len(flat) == 1 {
+		return flat[0]
+	}
+	return errors.Join(flat...)
 }
 
 // NArg returns the number of remaining arguments after parsing.
@@ -2,13 +2,29 @@ package command_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/michaeljpetter/command"
 	"github.com/michaeljpetter/command/check"
 	"github.com/michaeljpetter/command/flag"
+	"github.com/michaeljpetter/command/value"
 	"github.com/michaeljpetter/ptr"
 	"io"
+	"log/slog"
+	"math"
+	"net/netip"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"text/template"
+	"time"
 )
 
 func usageString(c *command.Command) string {
@@ -83,11 +99,11 @@ func TestCommandFlags(t *testing.T) {
 
 Options:
   -cab value
-    	cab feature
+    	cab feature (one of extended, super)
   -f value
-    	model num (default 150)
+    	model num (at least 150) (default 150)
   -yr value
-    	model year (default 2024)
+    	model year (at most 2024) (default 2024)
 ` {
 			t.Errorf("wrong usage:\n%v", usageString(cmd))
 		}
@@ -195,9 +211,9 @@ func TestCommandPositional(t *testing.T) {
   make a ford truck
 
 Arguments:
-  yr    model year
-  f     model num
-  cab   cab feature (default "")
+  yr    model year (at most 2024)
+  f     model num (at least 150)
+  cab   cab feature (one of extended, super) (default "")
 ` {
 			t.Errorf("wrong usage:\n%v", usageString(cmd))
 		}
@@ -462,3 +478,3133 @@ Commands:
 		t.Error("did not call handler")
 	}
 }
+
+func TestCommandCustom(t *testing.T) {
+	var addr netip.Addr
+	var level slog.Level
+	var ratio [2]int
+
+	parseRatio := func(raw string) ([2]int, error) {
+		var r [2]int
+		_, err := fmt.Sscanf(raw, "%d:%d", &r[0], &r[1])
+		return r, err
+	}
+	formatRatio := func(r [2]int) string {
+		return fmt.Sprintf("%d:%d", r[0], r[1])
+	}
+	checkRatio := func(r [2]int) error {
+		if r[1] == 0 {
+			return errors.New("cannot divide by zero")
+		}
+		return nil
+	}
+
+	buildCommand := func() *command.Command {
+		addr, level, ratio = netip.Addr{}, 0, [2]int{}
+		cmd := command.New("render", "render a scene", flag.ContinueOnError)
+		flag.TextVar(cmd.FlagSet, &level, "log", slog.LevelWarn, "log level")
+		flag.FuncVar(cmd.FlagSet, &ratio, "ratio", [2]int{16, 9}, "aspect ratio", parseRatio, formatRatio, checkRatio)
+		command.PositionalTextVar(cmd, &addr, "host", nil, "render host", func(a netip.Addr) error {
+			if !a.Is4() {
+				return errors.New("must be IPv4")
+			}
+			return nil
+		})
+		return cmd
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: render [options] <host>
+
+  render a scene
+
+Options:
+  -log value
+    	log level (default WARN)
+  -ratio value
+    	aspect ratio (default 16:9)
+
+Arguments:
+  host  render host
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"-log", "debug", "-ratio", "4:3", "10.0.0.1"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if level != slog.LevelDebug {
+			t.Errorf("wrong -log value %v, expected %v", level, slog.LevelDebug)
+		}
+		if ratio != [2]int{4, 3} {
+			t.Errorf("wrong -ratio value %v, expected %v", ratio, [2]int{4, 3})
+		}
+		if addr != netip.MustParseAddr("10.0.0.1") {
+			t.Errorf("wrong host value %v, expected %v", addr, "10.0.0.1")
+		}
+	})
+
+	t.Run("ArgFailsParse", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.SetOutput(io.Discard)
+		err := cmd.Parse([]string{"localhost"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if !strings.HasPrefix(err.Error(), `invalid value "localhost" for argument host: `) {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("ArgFailsCheck", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.SetOutput(io.Discard)
+
+		err := cmd.Parse([]string{"-ratio", "1:0", "10.0.0.1"})
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "1:0" for flag -ratio: cannot divide by zero` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd = buildCommand()
+		cmd.SetOutput(io.Discard)
+
+		err = cmd.Parse([]string{"::1"})
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "::1" for argument host: must be IPv4` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+func TestCommandNumeric(t *testing.T) {
+	buildCommand := func() (*command.Command, *int8, *uint16, *float32, *time.Duration) {
+		cmd := command.New("net", "network utility", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		ttl := cmd.Int8("ttl", 64, "time to live")
+		timeout := cmd.Duration("timeout", time.Second, "timeout")
+		port := cmd.PositionalUint16("port", nil, "port number", check.AtLeast[uint16](1))
+		loss := cmd.PositionalFloat32("loss", ptr.To[float32](0.5), "loss rate")
+		return cmd, ttl, port, loss, timeout
+	}
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		cmd, ttl, port, loss, timeout := buildCommand()
+		err := cmd.Parse([]string{"-ttl", "-0x80", "-timeout", "0x10ms", "0o17", "0b11"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *ttl != -128 {
+			t.Errorf("wrong -ttl value %v, expected %v", *ttl, -128)
+		}
+		if *timeout != 16*time.Millisecond {
+			t.Errorf("wrong -timeout value %v, expected %v", *timeout, 16*time.Millisecond)
+		}
+		if *port != 15 {
+			t.Errorf("wrong port value %v, expected %v", *port, 15)
+		}
+		if *loss != 3 {
+			t.Errorf("wrong loss value %v, expected %v", *loss, 3)
+		}
+	})
+
+	t.Run("ArgOutOfRange", func(t *testing.T) {
+		cmd, _, _, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-ttl", "128", "80"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "128" for flag -ttl: value out of range` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"65536"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "65536" for argument port: value out of range` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"80", "1e39"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "1e39" for argument loss: value out of range` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+func TestCommandUnits(t *testing.T) {
+	buildCommand := func() (*command.Command, *uint64, *float64, *float64) {
+		cmd := command.New("cache", "run a cache", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		memory := cmd.ByteSize("memory", 1536<<20, "memory limit", check.AtMost[uint64](8<<30))
+		rate := cmd.Quantity("rate", 2500, "requests per second")
+		ratio := cmd.PositionalPercent("ratio", ptr.To(0.75), "hit ratio", check.AtMost(1.))
+		return cmd, memory, rate, ratio
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: cache [options] [ratio]
+
+  run a cache
+
+Options:
+  -memory value
+    	memory limit (at most 8589934592) (default 1.5GiB)
+  -rate value
+    	requests per second (default 2.5k)
+
+Arguments:
+  ratio  hit ratio (at most 1) (default 75%)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		for _, test := range []struct {
+			memory, rate, ratio string
+			bytes               uint64
+			quantity, percent   float64
+		}{
+			{"512", "3.5M", "40%", 512, 3.5e6, 0.4},
+			{"10KB", "2k", "25%", 10000, 2000, 0.25},
+			{"1.5GiB", "250m", "100%", 1536 << 20, 0.25, 1},
+			{"4M", "7", "7.5 %", 4000000, 7, 0.075},
+			{"4mib", "1u", "0%", 4 << 20, 1e-6, 0},
+		} {
+			cmd, memory, rate, ratio := buildCommand()
+			err := cmd.Parse([]string{"-memory", test.memory, "-rate", test.rate, test.ratio})
+
+			if err != nil {
+				t.Fatalf("parse failed with %v", err)
+			}
+			if *memory != test.bytes {
+				t.Errorf("wrong -memory value %v, expected %v", *memory, test.bytes)
+			}
+			if *rate != test.quantity {
+				t.Errorf("wrong -rate value %v, expected %v", *rate, test.quantity)
+			}
+			if *ratio != test.percent {
+				t.Errorf("wrong ratio value %v, expected %v", *ratio, test.percent)
+			}
+		}
+	})
+
+	t.Run("ArgFailsParse", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-memory", "12 parsecs"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "12 parsecs" for flag -memory: parse error` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"-memory", "20EiB"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "20EiB" for flag -memory: value out of range` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		for _, ratio := range []string{"75", "0.75"} {
+			cmd, _, _, _ = buildCommand()
+			err = cmd.Parse([]string{ratio})
+
+			if err == nil {
+				t.Fatal("parse succeeded")
+			}
+			if err.Error() != `invalid value "`+ratio+`" for argument ratio: missing % suffix` {
+				t.Errorf("wrong error %v", err)
+			}
+		}
+	})
+
+	t.Run("ArgFailsCheck", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-memory", "9GiB"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "9GiB" for flag -memory: must be at most 8589934592` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"120%"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "120%" for argument ratio: must be at most 1` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("FormatByteSize", func(t *testing.T) {
+		for _, test := range []struct {
+			bytes    uint64
+			expected string
+		}{
+			{0, "0B"},
+			{1536 << 20, "1.5GiB"},
+			{25000, "25KB"},
+			{1 << 60, "1EiB"},
+			{1<<60 + 1, "1152921504606846977B"},
+			{1<<60 + 1<<59, "1.5EiB"},
+			{1<<64 - 1, "18446744073709551615B"},
+		} {
+			cmd, _, _, _ := buildCommand()
+			memory := cmd.Lookup("memory").Value
+			memory.Set(strconv.FormatUint(test.bytes, 10))
+
+			if memory.String() != test.expected {
+				t.Errorf("wrong format %v of %v bytes, expected %v", memory.String(), test.bytes, test.expected)
+			}
+		}
+	})
+}
+
+func TestCommandTime(t *testing.T) {
+	clock := func() time.Time { return time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC) }
+	times := value.TimeParser{Location: time.UTC, Now: clock}
+	dates := value.DateParser{Location: time.UTC, Now: clock}
+
+	buildCommand := func() (*command.Command, *time.Time, **time.Location, *time.Time) {
+		cmd := command.New("report", "generate a report", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		since := flag.Func(cmd.FlagSet, "since", clock().Add(-time.Hour), "report start", times.Parse, times.Format, check.Before(clock()))
+		zone := cmd.Location("tz", time.UTC, "report time zone")
+		day := command.PositionalFunc(cmd, "day", nil, "report day", dates.Parse, dates.Format)
+		return cmd, since, zone, day
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: report [options] <day>
+
+  generate a report
+
+Options:
+  -since value
+    	report start (before 2024-03-15T13:30:00Z) (default 2024-03-15T12:30:00Z)
+  -tz value
+    	report time zone (default UTC)
+
+Arguments:
+  day   report day
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		for _, test := range []struct {
+			args       []string
+			since, day time.Time
+		}{
+			{[]string{"2024-03-01"}, clock().Add(-time.Hour), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+			{[]string{"-since", "now-2h", "today"}, clock().Add(-2 * time.Hour), time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+			{[]string{"-since", "Yesterday+9h30m", "yesterday"}, time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC), time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+			{[]string{"-since", "2024-03-10T08:00:00+02:00", "tomorrow"}, time.Date(2024, 3, 10, 6, 0, 0, 0, time.UTC), time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+			{[]string{"-since", "2024-03-10 08:00:00", "2024-03-10"}, time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		} {
+			cmd, since, _, day := buildCommand()
+			err := cmd.Parse(test.args)
+
+			if err != nil {
+				t.Fatalf("parse failed with %v", err)
+			}
+			if !since.Equal(test.since) {
+				t.Errorf("wrong -since value %v, expected %v", since, test.since)
+			}
+			if !day.Equal(test.day) {
+				t.Errorf("wrong day value %v, expected %v", day, test.day)
+			}
+		}
+	})
+
+	t.Run("Location", func(t *testing.T) {
+		cmd, _, zone, _ := buildCommand()
+		err := cmd.Parse([]string{"-tz", "Local", "today"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *zone != time.Local {
+			t.Errorf("wrong -tz value %v, expected %v", *zone, time.Local)
+		}
+
+		cmd, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"-tz", "Nowhere/Special", "today"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "Nowhere/Special" for flag -tz: parse error` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("ArgFailsCheck", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-since", "tomorrow", "today"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "tomorrow" for flag -since: must be before 2024-03-15T13:30:00Z` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		cmd := command.New("report", "generate a report", flag.ContinueOnError)
+		at := cmd.Time("at", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "report time")
+		on := cmd.PositionalDate("on", ptr.To(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), "report date")
+
+		if usageString(cmd) !=
+			`Usage: report [options] [on]
+
+  generate a report
+
+Options:
+  -at value
+    	report time (default 2024-01-02T03:04:05Z)
+
+Arguments:
+  on    report date (default 2024-01-02)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+
+		if err := cmd.Parse([]string{"-at", "2024-05-06T07:08:09Z", "2024-05-06"}); err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if !at.Equal(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)) {
+			t.Errorf("wrong -at value %v", at)
+		}
+		if on.Year() != 2024 || on.Month() != 5 || on.Day() != 6 || on.Hour() != 0 {
+			t.Errorf("wrong on value %v", on)
+		}
+	})
+
+	t.Run("Layouts", func(t *testing.T) {
+		layouts := []string{"02 Jan 2006 15:04", time.RFC3339}
+		cmd := command.New("report", "generate a report", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		at := cmd.TimeLayouts("at", time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC), layouts, "report time")
+		until := cmd.PositionalTimeLayouts("until", nil, layouts, "report end")
+
+		if usageString(cmd) !=
+			`Usage: report [options] <until>
+
+  generate a report
+
+Options:
+  -at value
+    	report time (default 02 Jan 2024 03:04)
+
+Arguments:
+  until  report end
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+
+		if err := cmd.Parse([]string{"-at", "06 May 2024 07:08", "2024-05-07T00:00:00Z"}); err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if at.Year() != 2024 || at.Month() != 5 || at.Day() != 6 || at.Hour() != 7 || at.Minute() != 8 {
+			t.Errorf("wrong -at value %v", at)
+		}
+		if !until.Equal(time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("wrong until value %v", until)
+		}
+
+		err := cmd.Parse([]string{"-at", "2024-05-06 07:08:09", "now"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "2024-05-06 07:08:09" for flag -at: parse error` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+func TestCommandExtendedDuration(t *testing.T) {
+	buildCommand := func() (*command.Command, *time.Duration, *time.Duration) {
+		cmd := command.New("prune", "prune old backups", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		retain := cmd.ExtendedDuration("retain", 14*24*time.Hour, "retention period", check.AtLeast(24*time.Hour))
+		ttl := cmd.PositionalExtendedDuration("ttl", ptr.To(36*time.Hour), "lock ttl")
+		return cmd, retain, ttl
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: prune [options] [ttl]
+
+  prune old backups
+
+Options:
+  -retain value
+    	retention period (at least 24h0m0s) (default 2w)
+
+Arguments:
+  ttl   lock ttl (default 1d12h)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		for _, test := range []struct {
+			retain, ttl string
+			expected    [2]time.Duration
+		}{
+			{"7d", "90m", [2]time.Duration{7 * 24 * time.Hour, 90 * time.Minute}},
+			{"1w2d", "1d12h", [2]time.Duration{9 * 24 * time.Hour, 36 * time.Hour}},
+			{"P1DT2H", "pt30s", [2]time.Duration{26 * time.Hour, 30 * time.Second}},
+			{"1.5d", "PT0,5S", [2]time.Duration{36 * time.Hour, 500 * time.Millisecond}},
+		} {
+			cmd, retain, ttl := buildCommand()
+			err := cmd.Parse([]string{"-retain", test.retain, test.ttl})
+
+			if err != nil {
+				t.Fatalf("parse failed with %v", err)
+			}
+			if *retain != test.expected[0] {
+				t.Errorf("wrong -retain value %v, expected %v", *retain, test.expected[0])
+			}
+			if *ttl != test.expected[1] {
+				t.Errorf("wrong ttl value %v, expected %v", *ttl, test.expected[1])
+			}
+		}
+	})
+
+	t.Run("ArgFailsParse", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-retain", "P1Y"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "P1Y" for flag -retain: parse error` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("ArgFailsCheck", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-retain", "12h"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "12h" for flag -retain: must be at least 24h0m0s` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("Extremes", func(t *testing.T) {
+		for _, test := range []struct {
+			raw, expected string
+			duration      time.Duration
+		}{
+			{"-2562047h47m16.854775808s", "-106751d23h47m16.854775808s", math.MinInt64},
+			{"2562047h47m16.854775807s", "106751d23h47m16.854775807s", math.MaxInt64},
+			{"-2w", "-2w", -14 * 24 * time.Hour},
+			{"-36h", "-1d12h", -36 * time.Hour},
+			{"-90m", "-1h30m", -90 * time.Minute},
+		} {
+			cmd := command.New("prune", "prune old backups", flag.ContinueOnError)
+			age := cmd.ExtendedDuration("age", 0, "minimum age")
+			err := cmd.Parse([]string{"-age", test.raw})
+
+			if err != nil {
+				t.Fatalf("parse failed with %v", err)
+			}
+			if *age != test.duration {
+				t.Errorf("wrong -age value %v, expected %v", *age, test.duration)
+			}
+			if formatted := cmd.Lookup("age").Value.String(); formatted != test.expected {
+				t.Errorf("wrong format %v, expected %v", formatted, test.expected)
+			}
+		}
+	})
+}
+
+func TestCommandNetwork(t *testing.T) {
+	buildCommand := func() (*command.Command, **url.URL, *string, *netip.Prefix, *netip.Addr) {
+		cmd := command.New("proxy", "run a proxy", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		upstream := cmd.URL("upstream", &url.URL{Scheme: "https", Host: "example.com"}, "upstream url", check.Scheme("http", "https"))
+		listen := cmd.HostPort("listen", "localhost:8080", "8080", "listen address")
+		allow := cmd.Prefix("allow", netip.MustParsePrefix("10.0.0.0/8"), "allowed clients")
+		bind := cmd.PositionalAddr("bind", nil, "bind address", check.IsPrivate)
+		return cmd, upstream, listen, allow, bind
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: proxy [options] <bind>
+
+  run a proxy
+
+Options:
+  -allow value
+    	allowed clients (default 10.0.0.0/8)
+  -listen value
+    	listen address (default localhost:8080)
+  -upstream value
+    	upstream url (scheme one of http, https) (default https://example.com)
+
+Arguments:
+  bind  bind address (private)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		cmd, upstream, listen, allow, bind := buildCommand()
+		err := cmd.Parse([]string{"-upstream", "http://internal:9000/api", "-listen", "::1", "-allow", "fd00::/8", "fd00::2"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if (*upstream).String() != "http://internal:9000/api" {
+			t.Errorf("wrong -upstream value %v, expected %v", *upstream, "http://internal:9000/api")
+		}
+		if *listen != "[::1]:8080" {
+			t.Errorf("wrong -listen value %v, expected %v", *listen, "[::1]:8080")
+		}
+		if *allow != netip.MustParsePrefix("fd00::/8") {
+			t.Errorf("wrong -allow value %v, expected %v", *allow, "fd00::/8")
+		}
+		if *bind != netip.MustParseAddr("fd00::2") {
+			t.Errorf("wrong bind value %v, expected %v", *bind, "fd00::2")
+		}
+	})
+
+	t.Run("ArgFailsParse", func(t *testing.T) {
+		cmd, _, _, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-listen", "localhost:http", "10.0.0.1"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "localhost:http" for flag -listen: invalid port` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("ArgFailsCheck", func(t *testing.T) {
+		cmd, _, _, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-upstream", "ftp://example.com", "10.0.0.1"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "ftp://example.com" for flag -upstream: scheme must be one of [http https]` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"8.8.8.8"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "8.8.8.8" for argument bind: must be a private address` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+func TestCommandEnum(t *testing.T) {
+	type format int
+	const (
+		formatJSON format = iota
+		formatYAML
+		formatText
+	)
+	formats := value.Enum[format]{
+		Choices: []value.Choice[format]{
+			{Name: "json", Value: formatJSON},
+			{Name: "yaml", Value: formatYAML, Aliases: []string{"yml"}},
+			{Name: "text", Value: formatText},
+		},
+		FoldCase: true,
+	}
+	levels := value.Enum[string]{
+		Choices: []value.Choice[string]{{Name: "low", Value: "L"}, {Name: "high", Value: "H"}},
+	}
+
+	buildCommand := func() (*command.Command, *format, *string) {
+		cmd := command.New("dump", "dump records", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		output := flag.Enum(cmd.FlagSet, "o", formatText, "output format", formats)
+		level := command.PositionalEnum(cmd, "level", nil, "detail level", levels)
+		return cmd, output, level
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: dump [options] <level>
+
+  dump records
+
+Options:
+  -o value
+    	output format (one of: json, yaml, text) (default text)
+
+Arguments:
+  level  detail level (one of: low, high)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		for _, test := range []struct {
+			output, level string
+			expected      format
+			expectedLevel string
+		}{
+			{"json", "low", formatJSON, "L"},
+			{"YML", "high", formatYAML, "H"},
+			{"Text", "low", formatText, "L"},
+		} {
+			cmd, output, level := buildCommand()
+			err := cmd.Parse([]string{"-o", test.output, test.level})
+
+			if err != nil {
+				t.Fatalf("parse failed with %v", err)
+			}
+			if *output != test.expected {
+				t.Errorf("wrong -o value %v, expected %v", *output, test.expected)
+			}
+			if *level != test.expectedLevel {
+				t.Errorf("wrong level value %v, expected %v", *level, test.expectedLevel)
+			}
+		}
+	})
+
+	t.Run("ArgFailsParse", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+		err := cmd.Parse([]string{"-o", "xml", "low"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "xml" for flag -o: must be one of json, yaml, text` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _ = buildCommand()
+		err = cmd.Parse([]string{"LOW"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "LOW" for argument level: must be one of low, high` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+func TestCommandStructured(t *testing.T) {
+	buildCommand := func() (*command.Command, *value.Pair[string, int], *value.Range[uint16], *[]time.Duration) {
+		cmd := command.New("scan", "scan ports", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		label := command.PositionalPair[string, int](cmd, "label", nil, "label weight", check.AtLeast(0))
+		ports := command.PositionalRange[uint16](cmd, "ports", nil, "port range", check.AtLeast[uint16](1024))
+		delays := command.PositionalList(cmd, "delays", &[]time.Duration{time.Second, 2 * time.Second}, "retry delays")
+		return cmd, label, ports, delays
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: scan <label> <ports> [delays]
+
+  scan ports
+
+Arguments:
+  label   label weight (at least 0)
+  ports   port range (at least 1024)
+  delays  retry delays (default 1s,2s)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		for _, test := range []struct {
+			args   []string
+			label  value.Pair[string, int]
+			ports  value.Range[uint16]
+			delays []time.Duration
+		}{
+			{
+				[]string{"team=5", "8000-8010"},
+				value.Pair[string, int]{Key: "team", Value: 5},
+				value.Range[uint16]{Lo: 8000, Hi: 8010, HasLo: true, HasHi: true},
+				[]time.Duration{time.Second, 2 * time.Second},
+			},
+			{
+				[]string{"a=0", "8000..<9000", "1m, 90s"},
+				value.Pair[string, int]{Key: "a", Value: 0},
+				value.Range[uint16]{Lo: 8000, Hi: 9000, HasLo: true, HasHi: true, Exclusive: true},
+				[]time.Duration{time.Minute, 90 * time.Second},
+			},
+			{
+				[]string{"x=0x10", "1024:2000", ""},
+				value.Pair[string, int]{Key: "x", Value: 16},
+				value.Range[uint16]{Lo: 1024, Hi: 2000, HasLo: true, HasHi: true},
+				nil,
+			},
+			{
+				[]string{"x=1", "5000.."},
+				value.Pair[string, int]{Key: "x", Value: 1},
+				value.Range[uint16]{Lo: 5000, HasLo: true},
+				[]time.Duration{time.Second, 2 * time.Second},
+			},
+			{
+				[]string{" x = 7 ", "5000.."},
+				value.Pair[string, int]{Key: "x", Value: 7},
+				value.Range[uint16]{Lo: 5000, HasLo: true},
+				[]time.Duration{time.Second, 2 * time.Second},
+			},
+			{
+				[]string{"x=1", "5000"},
+				value.Pair[string, int]{Key: "x", Value: 1},
+				value.Range[uint16]{Lo: 5000, Hi: 5000, HasLo: true, HasHi: true},
+				[]time.Duration{time.Second, 2 * time.Second},
+			},
+		} {
+			cmd, label, ports, delays := buildCommand()
+			err := cmd.Parse(test.args)
+
+			if err != nil {
+				t.Fatalf("parse failed with %v", err)
+			}
+			if *label != test.label {
+				t.Errorf("wrong label value %v, expected %v", *label, test.label)
+			}
+			if *ports != test.ports {
+				t.Errorf("wrong ports value %v, expected %v", *ports, test.ports)
+			}
+			if !slices.Equal(*delays, test.delays) {
+				t.Errorf("wrong delays value %v, expected %v", *delays, test.delays)
+			}
+		}
+	})
+
+	t.Run("RangeContains", func(t *testing.T) {
+		r := value.Range[int]{Lo: 1, Hi: 5, HasLo: true, HasHi: true, Exclusive: true}
+
+		if !r.Contains(1) || !r.Contains(4) {
+			t.Error("did not contain value within bounds")
+		}
+		if r.Contains(0) || r.Contains(5) {
+			t.Error("contained value outside bounds")
+		}
+		if r.String() != "1..<5" {
+			t.Errorf("wrong string %v, expected %v", r.String(), "1..<5")
+		}
+	})
+
+	t.Run("ArgFailsParse", func(t *testing.T) {
+		for _, test := range []struct {
+			args     []string
+			expected string
+		}{
+			{[]string{"novalue", "1-2"}, `invalid value "novalue" for argument label: parse error`},
+			{[]string{"a=b=1", "1-2"}, `invalid value "a=b=1" for argument label: parse error`},
+			{[]string{"=1", "1-2"}, `invalid value "=1" for argument label: missing key`},
+			{[]string{"a=1", "9000-8000"}, `invalid value "9000-8000" for argument ports: lower bound exceeds upper bound`},
+			{[]string{"a=1", "8000-70000"}, `invalid value "8000-70000" for argument ports: value out of range`},
+			{[]string{"a=1", "8000-"}, `invalid value "8000-" for argument ports: parse error`},
+			{[]string{"a=1", "8000", "1s,soon"}, `invalid value "1s,soon" for argument delays: parse error`},
+		} {
+			cmd, _, _, _ := buildCommand()
+			err := cmd.Parse(test.args)
+
+			if err == nil {
+				t.Fatal("parse succeeded")
+			}
+			if err.Error() != test.expected {
+				t.Errorf("wrong error %v", err)
+			}
+		}
+	})
+
+	t.Run("ArgFailsCheck", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand()
+		err := cmd.Parse([]string{"a=-1", "1-2"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "a=-1" for argument label: must be at least 0` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"a=1", "80-8080"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "80-8080" for argument ports: must be at least 1024` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _, _ = buildCommand()
+		err = cmd.Parse([]string{"a=1", ":2000"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value ":2000" for argument ports: must be at least 1024` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("RangeInteriorFailsCheck", func(t *testing.T) {
+		buildCommand := func() (*command.Command, *value.Range[int]) {
+			cmd := command.New("scan", "scan ports", flag.ContinueOnError)
+			cmd.SetOutput(io.Discard)
+			ports := command.PositionalRange[int](cmd, "ports", nil, "port range", func(port int) error {
+				if port == 8005 {
+					return errors.New("port 8005 is reserved")
+				}
+				return nil
+			})
+			return cmd, ports
+		}
+
+		for _, test := range []struct {
+			arg      string
+			expected string
+		}{
+			{"8000-8010", `invalid value "8000-8010" for argument ports: port 8005 is reserved`},
+			{"8005..<8006", `invalid value "8005..<8006" for argument ports: port 8005 is reserved`},
+			{"8000..", `invalid value "8000.." for argument ports: range exceeds 1048576 values to check`},
+		} {
+			cmd, _ := buildCommand()
+			err := cmd.Parse([]string{test.arg})
+
+			if err == nil {
+				t.Fatalf("parse of %v succeeded", test.arg)
+			}
+			if err.Error() != test.expected {
+				t.Errorf("wrong error %v", err)
+			}
+		}
+
+		for _, arg := range []string{"8000-8004", "8006..8010", "8000..<8005", "8005..<8005"} {
+			cmd, _ := buildCommand()
+			if err := cmd.Parse([]string{arg}); err != nil {
+				t.Errorf("parse of %v failed with %v", arg, err)
+			}
+		}
+
+		cmd := command.New("scan", "scan ports", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		command.PositionalRange[float64](cmd, "ratio", nil, "ratio range", func(ratio float64) error {
+			if ratio == 0.5 {
+				return errors.New("must not be half")
+			}
+			return nil
+		})
+		if err := cmd.Parse([]string{"0..1"}); err != nil {
+			t.Errorf("parse failed with %v", err)
+		}
+	})
+
+	t.Run("PairKeyFailsCheck", func(t *testing.T) {
+		cmd := command.New("tag", "tag a truck", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		command.PositionalPair[string, string](cmd, "tag", nil, "tag to set", check.NotBlank, check.OneOf("red", "blue"))
+		err := cmd.Parse([]string{"green=red"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "green=red" for argument tag: must be one of [red blue]` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+type fakeTerminal struct {
+	interactive bool
+	input       string
+	prompts     *[]string
+}
+
+func (f fakeTerminal) IsTerminal() bool {
+	return f.interactive
+}
+
+func (f fakeTerminal) ReadLine(prompt string) (string, error) {
+	return "", errors.New("not a secret")
+}
+
+func (f fakeTerminal) ReadSecret(prompt string) (string, error) {
+	*f.prompts = append(*f.prompts, prompt)
+	return f.input, nil
+}
+
+func TestCommandSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	buildCommand := func() (*command.Command, *string, *string) {
+		cmd := command.New("login", "log in to a service", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		password := cmd.Secret("password", "hunter2", "account password", check.NotBlank)
+		token := cmd.PositionalSecret("token", nil, "api token", func(token string) error {
+			if !strings.HasPrefix(token, "tok_") {
+				return errors.New("must start with tok_")
+			}
+			return nil
+		})
+		return cmd, password, token
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: login [options] <token>
+
+  log in to a service
+
+Options:
+  -password value
+    	account password (not blank) (default ********)
+
+Arguments:
+  token  api token
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("ValidArgs", func(t *testing.T) {
+		cmd, password, token := buildCommand()
+		err := cmd.Parse([]string{"-password", "@" + path, "tok_abc"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *password != "from-file" {
+			t.Errorf("wrong -password value %v, expected %v", *password, "from-file")
+		}
+		if *token != "tok_abc" {
+			t.Errorf("wrong token value %v, expected %v", *token, "tok_abc")
+		}
+
+		cmd, password, _ = buildCommand()
+		err = cmd.Parse([]string{"-password", "@@literal", "tok_abc"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *password != "@literal" {
+			t.Errorf("wrong -password value %v, expected %v", *password, "@literal")
+		}
+	})
+
+	t.Run("ArgFailsCheck", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+		out := new(bytes.Buffer)
+		cmd.SetOutput(out)
+		err := cmd.Parse([]string{"-password", "  ", "tok_abc"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "********" for flag -password: cannot be blank` {
+			t.Errorf("wrong error %v", err)
+		}
+
+		cmd, _, _ = buildCommand()
+		cmd.SetOutput(out)
+		err = cmd.Parse([]string{"sekrit"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "********" for argument token: must start with tok_` {
+			t.Errorf("wrong error %v", err)
+		}
+		if strings.Contains(out.String(), "sekrit") {
+			t.Errorf("secret leaked to output:\n%v", out.String())
+		}
+	})
+
+	t.Run("Prompt", func(t *testing.T) {
+		var prompts []string
+
+		cmd, _, token := buildCommand()
+		cmd.Terminal = fakeTerminal{true, "tok_typed", &prompts}
+		err := cmd.Parse(nil)
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *token != "tok_typed" {
+			t.Errorf("wrong token value %v, expected %v", *token, "tok_typed")
+		}
+		if !slices.Equal(prompts, []string{"token (api token): "}) {
+			t.Errorf("wrong prompts %v", prompts)
+		}
+
+		cmd, _, _ = buildCommand()
+		cmd.Terminal = fakeTerminal{false, "tok_typed", &prompts}
+		err = cmd.Parse(nil)
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `missing argument for <token>` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("PromptLiteral", func(t *testing.T) {
+		for _, typed := range []string{"-", "@tok_file"} {
+			cmd := command.New("login", "log in to a service", flag.ContinueOnError)
+			cmd.SetOutput(io.Discard)
+			cmd.Terminal = fakeTerminal{true, typed, new([]string)}
+			token := cmd.PositionalSecret("token", nil, "api token")
+			err := cmd.Parse(nil)
+
+			if err != nil {
+				t.Fatalf("parse failed with %v", err)
+			}
+			if *token != typed {
+				t.Errorf("wrong token value %v, expected %v", *token, typed)
+			}
+		}
+	})
+
+	t.Run("StdinInShell", func(t *testing.T) {
+		var token *string
+		cmd := command.New("client", "service client", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		cmd.SetShell(command.Shell{In: strings.NewReader("login -\ntok_piped\n"), Out: io.Discard})
+		cmd.Define("login", "log in to a service", func(cmd *command.Command) {
+			token = cmd.PositionalSecret("token", nil, "api token")
+		}, nil)
+
+		if err := cmd.Parse([]string{"shell"}); err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *token != "tok_piped" {
+			t.Errorf("wrong token value %v, expected %v", *token, "tok_piped")
+		}
+	})
+
+	t.Run("PromptInvalid", func(t *testing.T) {
+		var prompts []string
+
+		cmd, _, _ := buildCommand()
+		cmd.Terminal = fakeTerminal{true, "sekrit", &prompts}
+		err := cmd.Parse(nil)
+
+		var argErr *command.ArgumentError
+		if !errors.As(err, &argErr) {
+			t.Fatalf("wrong error %v, expected *command.ArgumentError", err)
+		}
+		if argErr.Name != "token" || argErr.Err == nil {
+			t.Errorf("wrong argument error %+v", argErr)
+		}
+		if err.Error() != `invalid value "********" for argument token: must start with tok_` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+func TestCommandEnv(t *testing.T) {
+	buildCommand := func() (*command.Command, *int) {
+		cmd := command.New("haul", "haul a load", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		weight := cmd.Int("weight", 100, "load weight", check.AtMost(500))
+		cmd.Secret("token", "", "dispatch token")
+		cmd.DocumentEnv("weight", "HAUL_WEIGHT")
+		cmd.DocumentEnv("token", "HAUL_TOKEN")
+		return cmd, weight
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: haul [options]
+
+  haul a load
+
+Options:
+  -token value
+    	dispatch token (env: HAUL_TOKEN)
+  -weight value
+    	load weight (at most 500) (env: HAUL_WEIGHT) (default 100)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+		if env := cmd.Describe().Flags[1].Env; env != "HAUL_WEIGHT" {
+			t.Errorf("wrong env %v, expected %v", env, "HAUL_WEIGHT")
+		}
+	})
+
+	t.Run("NotRead", func(t *testing.T) {
+		t.Setenv("HAUL_WEIGHT", "250")
+		cmd, weight := buildCommand()
+		err := cmd.Parse(nil)
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *weight != 100 {
+			t.Errorf("wrong -weight value %v, expected %v", *weight, 100)
+		}
+	})
+}
+
+func TestCommandHidden(t *testing.T) {
+	buildCommand := func() (*command.Command, *bool, *bool) {
+		cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		cmd.Help = true
+		cmd.Bool("v", false, "verbose")
+		trace := cmd.Bool("trace", false, "trace requests")
+		cmd.Hide("trace")
+		cmd.Subcommand("buy", "buy a stock truck", func(command.Bound) {})
+		reset := new(bool)
+		cmd.Define("admin", "administer the depot", func(cmd *command.Command) {
+			cmd.Define("reset", "reset all data", nil, func(command.Bound) { *reset = true })
+		}, nil)
+		cmd.HideSubcommand("admin")
+		return cmd, trace, reset
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: trucker [options] <command>
+
+  truck utility
+
+Options:
+  -v	verbose
+
+Commands:
+  buy   buy a stock truck
+  help  show help for a command
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+
+		d := cmd.Describe()
+		if !d.Flags[0].Hidden || d.Flags[1].Hidden {
+			t.Errorf("wrong hidden flags %v", d.Flags)
+		}
+		if !d.Subcommands[0].Hidden || d.Subcommands[1].Hidden {
+			t.Errorf("wrong hidden subcommands %v", d.Subcommands)
+		}
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+
+		if candidates := cmd.Complete([]string{""}); !slices.Equal(candidates, []string{"buy", "help"}) {
+			t.Errorf("wrong candidates %q", candidates)
+		}
+		if candidates := cmd.Complete([]string{"-"}); !slices.Equal(candidates, []string{"-v"}) {
+			t.Errorf("wrong candidates %q", candidates)
+		}
+		if candidates := cmd.Complete([]string{"admin", ""}); !slices.Equal(candidates, []string{"help", "reset"}) {
+			t.Errorf("wrong candidates %q", candidates)
+		}
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		cmd, trace, reset := buildCommand()
+		err := cmd.Parse([]string{"-trace", "admin", "reset"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if !*trace {
+			t.Error("-trace not set")
+		}
+		if !*reset {
+			t.Error("reset not run")
+		}
+	})
+
+	t.Run("Walk", func(t *testing.T) {
+		cmd, _, _ := buildCommand()
+
+		var hidden []string
+		cmd.Walk(func(c *command.Command) error {
+			if c.Describe().Hidden {
+				hidden = append(hidden, c.Name())
+			}
+			return nil
+		})
+
+		if !slices.Equal(hidden, []string{"trucker admin", "trucker admin reset"}) {
+			t.Errorf("wrong hidden commands %q", hidden)
+		}
+	})
+}
+
+func TestCommandPrompt(t *testing.T) {
+	levels := value.Enum[string]{
+		Choices: []value.Choice[string]{{Name: "low", Value: "low"}, {Name: "high", Value: "high"}},
+	}
+
+	buildCommand := func(input string) (*command.Command, *bytes.Buffer, *int, *string) {
+		out := new(bytes.Buffer)
+		cmd := command.New("batch", "run a batch", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		cmd.Terminal = command.NewTerminal(strings.NewReader(input), out)
+		cmd.Prompt = true
+		count := cmd.PositionalInt("count", nil, "batch count", check.AtLeast(1))
+		level := command.PositionalEnum(cmd, "level", nil, "priority level", levels)
+		return cmd, out, count, level
+	}
+
+	t.Run("Prompted", func(t *testing.T) {
+		cmd, out, count, level := buildCommand("many\n0\n3\r\nhigh\n")
+		err := cmd.Parse(nil)
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *count != 3 {
+			t.Errorf("wrong count value %v, expected %v", *count, 3)
+		}
+		if *level != "high" {
+			t.Errorf("wrong level value %v, expected %v", *level, "high")
+		}
+		if out.String() !=
+			`count (batch count): invalid value "many" for argument count: parse error
+count (batch count): invalid value "0" for argument count: must be at least 1
+count (batch count): level (priority level) (one of: low, high): ` {
+			t.Errorf("wrong prompts:\n%v", out.String())
+		}
+	})
+
+	t.Run("PartiallyGiven", func(t *testing.T) {
+		cmd, out, count, level := buildCommand("low")
+		err := cmd.Parse([]string{"7"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if *count != 7 {
+			t.Errorf("wrong count value %v, expected %v", *count, 7)
+		}
+		if *level != "low" {
+			t.Errorf("wrong level value %v, expected %v", *level, "low")
+		}
+		if out.String() != `level (priority level) (one of: low, high): ` {
+			t.Errorf("wrong prompts:\n%v", out.String())
+		}
+	})
+
+	t.Run("EndOfInput", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand("0\n")
+		err := cmd.Parse(nil)
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `missing argument for <count>` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("ReadError", func(t *testing.T) {
+		cmd, _, _, _ := buildCommand("")
+		cmd.Terminal = fakeTerminal{true, "", new([]string)}
+		err := cmd.Parse(nil)
+
+		var argErr *command.ArgumentError
+		if !errors.As(err, &argErr) {
+			t.Fatalf("wrong error %v, expected *command.ArgumentError", err)
+		}
+		if err.Error() != `cannot read argument for <count>: not a secret` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("NotTerminal", func(t *testing.T) {
+		file, err := os.CreateTemp(t.TempDir(), "stdin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		cmd, out, _, _ := buildCommand("")
+		cmd.Terminal = command.NewTerminal(file, out)
+		err = cmd.Parse(nil)
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `missing argument for <count>` {
+			t.Errorf("wrong error %v", err)
+		}
+		if out.Len() != 0 {
+			t.Errorf("prompted:\n%v", out.String())
+		}
+	})
+}
+
+func TestCommandHelp(t *testing.T) {
+	var called []string
+
+	buildCommand := func(errorHandling flag.ErrorHandling) (*command.Command, *bytes.Buffer) {
+		called = nil
+		buf := new(bytes.Buffer)
+		cmd := command.New("kube", "cluster utility", errorHandling)
+		cmd.SetOutput(buf)
+		cmd.Help = true
+		cmd.Use(func(next command.HandlerFunc) command.HandlerFunc {
+			return func(b command.Bound) {
+				called = append(called, b.Name())
+				next(b)
+			}
+		})
+		cmd.Define("cluster", "manage clusters", func(cmd *command.Command) {
+			cmd.SetOutput(buf)
+			cmd.Define("node", "manage nodes", func(cmd *command.Command) {
+				cmd.SetOutput(buf)
+				cmd.Bool("force", false, "force it")
+			}, func(cmd command.Bound) {
+				cmd.Parse()
+			})
+		}, nil)
+		return cmd, buf
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _ := buildCommand(flag.ContinueOnError)
+
+		if usageString(cmd) !=
+			`Usage: kube <command>
+
+  cluster utility
+
+Commands:
+  cluster  manage clusters
+  help     show help for a command
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Subcommand", func(t *testing.T) {
+		cmd, buf := buildCommand(flag.ContinueOnError)
+		err := cmd.Parse([]string{"help", "cluster", "node"})
+
+		if err != flag.ErrHelp {
+			t.Errorf("wrong error %v, expected %v", err, flag.ErrHelp)
+		}
+		if called != nil {
+			t.Errorf("called handlers %v", called)
+		}
+		if buf.String() !=
+			`Usage: kube cluster node [options]
+
+  manage nodes
+
+Options:
+  -force
+    	force it
+` {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("Inherited", func(t *testing.T) {
+		cmd, buf := buildCommand(flag.ContinueOnError)
+		cmd.Parse([]string{"cluster", "help", "node"})
+
+		if expected := []string{"kube cluster"}; !slices.Equal(called, expected) {
+			t.Errorf("wrong handlers %v, expected %v", called, expected)
+		}
+		if !strings.HasPrefix(buf.String(), "Usage: kube cluster node [options]\n") {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		cmd, buf := buildCommand(flag.ContinueOnError)
+		err := cmd.Parse([]string{"help"})
+
+		if err != flag.ErrHelp {
+			t.Errorf("wrong error %v, expected %v", err, flag.ErrHelp)
+		}
+		if buf.String() != usageString(cmd) {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		for _, arg := range []string{"-h", "-help", "--help"} {
+			cmd, buf := buildCommand(flag.ContinueOnError)
+			err := cmd.Parse([]string{arg})
+
+			if err != flag.ErrHelp {
+				t.Errorf("wrong error %v for %s, expected %v", err, arg, flag.ErrHelp)
+			}
+			if buf.String() != usageString(cmd) {
+				t.Errorf("wrong output for %s:\n%v", arg, buf.String())
+			}
+		}
+	})
+
+	t.Run("UnknownCommand", func(t *testing.T) {
+		cmd, _ := buildCommand(flag.ContinueOnError)
+		err := cmd.Parse([]string{"help", "pod"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `unknown command: pod` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("Defined", func(t *testing.T) {
+		var called bool
+		cmd, _ := buildCommand(flag.ContinueOnError)
+		cmd.Subcommand("help", "custom help", func(command.Bound) { called = true })
+
+		if err := cmd.Parse([]string{"help"}); err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if !called {
+			t.Error("did not call help handler")
+		}
+		if !strings.Contains(usageString(cmd), "  help     custom help\n") {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd, _ := buildCommand(flag.ContinueOnError)
+		cmd.Help = false
+		err := cmd.Parse([]string{"help"})
+
+		if err == nil || err.Error() != `unknown command: help` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		for _, args := range [][]string{{"-h"}, {"help"}} {
+			cmd, _ := buildCommand(flag.PanicOnError)
+
+			func() {
+				defer func() {
+					if r := recover(); r != flag.ErrHelp {
+						t.Errorf("wrong panic %v for %v, expected %v", r, args, flag.ErrHelp)
+					}
+				}()
+				cmd.Parse(args)
+			}()
+		}
+	})
+}
+
+func TestCommandVersion(t *testing.T) {
+	info := command.VersionInfo{
+		Version:   "v1.2.3",
+		Revision:  "abc123",
+		Modified:  true,
+		Time:      "2024-06-01T12:00:00Z",
+		GoVersion: "go1.23.1",
+	}
+
+	buildCommand := func(version command.Version) (*command.Command, *bytes.Buffer) {
+		buf := new(bytes.Buffer)
+		cmd := command.New("ship", "shipping utility", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		cmd.Help = true
+		version.Out = buf
+		cmd.SetVersion(version)
+		cmd.Subcommand("send", "send a package", func(command.Bound) {})
+		return cmd, buf
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd, _ := buildCommand(command.Version{Info: info})
+
+		if usageString(cmd) !=
+			`Usage: ship [options] <command>
+
+  shipping utility
+
+Options:
+  -version
+    	print version information and exit
+
+Commands:
+  help     show help for a command
+  send     send a package
+  version  show version information
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{Info: info})
+		err := cmd.Parse([]string{"-version"})
+
+		if err != command.ErrVersion {
+			t.Errorf("wrong error %v, expected %v", err, command.ErrVersion)
+		}
+		if buf.String() != "ship v1.2.3 (abc123, modified) built 2024-06-01T12:00:00Z go1.23.1\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("FlagJSON", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{Info: info})
+		err := cmd.Parse([]string{"-version=json"})
+
+		if err != command.ErrVersion {
+			t.Errorf("wrong error %v, expected %v", err, command.ErrVersion)
+		}
+		if buf.String() != `{"name":"ship","version":"v1.2.3","revision":"abc123","modified":true,`+
+			`"time":"2024-06-01T12:00:00Z","goVersion":"go1.23.1"}`+"\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("FlagArgumentNotJSON", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{Info: command.VersionInfo{Version: "1.0"}})
+		err := cmd.Parse([]string{"-version", "json"})
+
+		if err != command.ErrVersion {
+			t.Errorf("wrong error %v, expected %v", err, command.ErrVersion)
+		}
+		if buf.String() != "ship 1.0\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("Shell", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{Info: command.VersionInfo{Version: "1.0"}})
+		shellOut := new(bytes.Buffer)
+		cmd.SetShell(command.Shell{Prompt: "> ", In: strings.NewReader("version\n"), Out: shellOut})
+
+		if err := cmd.Parse([]string{"shell"}); err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if shellOut.String() != "> ship 1.0\n> \n" {
+			t.Errorf("wrong shell output %q", shellOut.String())
+		}
+		if buf.Len() != 0 {
+			t.Errorf("wrong version output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("Subcommand", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{Info: command.VersionInfo{Version: "1.0"}})
+		err := cmd.Parse([]string{"version"})
+
+		if err != command.ErrVersion {
+			t.Errorf("wrong error %v, expected %v", err, command.ErrVersion)
+		}
+		if buf.String() != "ship 1.0\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("SubcommandJSON", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{Info: command.VersionInfo{Name: "shipper", Version: "1.0"}})
+		err := cmd.Parse([]string{"version", "-json"})
+
+		if err != command.ErrVersion {
+			t.Errorf("wrong error %v, expected %v", err, command.ErrVersion)
+		}
+		if buf.String() != `{"name":"shipper","version":"1.0"}`+"\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("SubcommandHelp", func(t *testing.T) {
+		cmd, out := buildCommand(command.Version{Info: info})
+		buf := new(bytes.Buffer)
+		cmd.SetOutput(buf)
+		err := cmd.Parse([]string{"help", "version"})
+
+		if err != flag.ErrHelp {
+			t.Errorf("wrong error %v, expected %v", err, flag.ErrHelp)
+		}
+		if buf.String() !=
+			`Usage: ship version [options]
+
+  show version information
+
+Options:
+  -json
+    	print version information as JSON
+` {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+		if out.Len() != 0 {
+			t.Errorf("wrong version output:\n%v", out.String())
+		}
+	})
+
+	t.Run("SubcommandInvalid", func(t *testing.T) {
+		cmd, out := buildCommand(command.Version{Info: info})
+		buf := new(bytes.Buffer)
+		cmd.SetOutput(buf)
+		err := cmd.Parse([]string{"version", "-yaml"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `flag provided but not defined: -yaml` {
+			t.Errorf("wrong error %v", err)
+		}
+		if strings.Count(buf.String(), "Usage:") != 1 {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+		if out.Len() != 0 {
+			t.Errorf("wrong version output:\n%v", out.String())
+		}
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{
+			Info:     info,
+			Template: "{{.Version}}+{{.Revision}}\n",
+		})
+		cmd.Parse([]string{"-version"})
+
+		if buf.String() != "v1.2.3+abc123\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("DefaultJSON", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{Info: command.VersionInfo{Version: "1.0"}, JSON: true})
+		cmd.Parse([]string{"-version"})
+
+		if buf.String() != `{"name":"ship","version":"1.0"}`+"\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("RequiredPositional", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		cmd := command.New("ship", "shipping utility", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		cmd.SetVersion(command.Version{Info: command.VersionInfo{Version: "1.0"}, Out: buf})
+		cmd.PositionalString("package", nil, "package to send")
+		err := cmd.Parse([]string{"-version"})
+
+		if err != command.ErrVersion {
+			t.Errorf("wrong error %v, expected %v", err, command.ErrVersion)
+		}
+		if buf.String() != "ship 1.0\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("BuildInfo", func(t *testing.T) {
+		cmd, buf := buildCommand(command.Version{})
+		cmd.Parse([]string{"-version=json"})
+
+		expected := command.BuildVersionInfo()
+		expected.Name = "ship"
+
+		var actual command.VersionInfo
+		if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+			t.Fatalf("unmarshal failed with %v", err)
+		}
+		if actual != expected {
+			t.Errorf("wrong info %v, expected %v", actual, expected)
+		}
+		if actual.GoVersion != runtime.Version() {
+			t.Errorf("wrong go version %v, expected %v", actual.GoVersion, runtime.Version())
+		}
+	})
+}
+
+func TestCommandWrap(t *testing.T) {
+	buildCommand := func() *command.Command {
+		cmd := command.New("haul", "move freight between depots, optionally stopping along the way\nsee the manual", flag.ContinueOnError)
+		cmd.Wrap = true
+		cmd.Width = 40
+		cmd.String("route", "direct", "the route to take, which may include any number of stops")
+		cmd.Bool("v", false, "log each stop along the route as it is reached")
+		cmd.PositionalString("from", nil, "the depot from which freight departs")
+		cmd.PositionalString("to", ptr.To("home"), "the depot at which freight arrives")
+		return cmd
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: haul [options] <from> [to]
+
+  move freight between depots,
+  optionally stopping along the way
+  see the manual
+
+Options:
+  -route value
+    	the route to take, which may
+    	include any number of stops
+    	(default "direct")
+  -v	log each stop along the route as
+    	it is reached
+
+Arguments:
+  from  the depot from which freight
+        departs
+  to    the depot at which freight
+        arrives (default "home")
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Subcommands", func(t *testing.T) {
+		cmd := command.New("haul", "freight utility", flag.ContinueOnError)
+		cmd.Wrap = true
+		cmd.Width = 30
+		cmd.Subcommand("load", "load freight onto a waiting truck", func(command.Bound) {})
+
+		if usageString(cmd) !=
+			`Usage: haul <command>
+
+  freight utility
+
+Commands:
+  load  load freight onto a
+        waiting truck
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Columns", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.Width = 0
+		t.Setenv("COLUMNS", "32")
+
+		if cmd.WrapWidth() != 32 {
+			t.Errorf("wrong width %v, expected %v", cmd.WrapWidth(), 32)
+		}
+
+		t.Setenv("COLUMNS", "")
+
+		if cmd.WrapWidth() != 80 {
+			t.Errorf("wrong width %v, expected %v", cmd.WrapWidth(), 80)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.Wrap = false
+
+		if cmd.WrapWidth() != 0 {
+			t.Errorf("wrong width %v, expected %v", cmd.WrapWidth(), 0)
+		}
+		if !strings.Contains(usageString(cmd), "\n  from  the depot from which freight departs\n") {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+}
+
+func TestCommandUsageTemplate(t *testing.T) {
+	buildCommand := func() *command.Command {
+		cmd := command.New("stock", "inventory utility", flag.ContinueOnError)
+		cmd.Int("count", 1, "number of `items`")
+		cmd.String("sku", "", "item to stock")
+		command.PositionalEnum(cmd, "bin", ptr.To("a"), "bin to stock", value.Enum[string]{
+			Choices: []value.Choice[string]{{Name: "a", Value: "a"}, {Name: "b", Value: "b"}},
+		})
+		return cmd
+	}
+
+	t.Run("Describe", func(t *testing.T) {
+		d := buildCommand().Describe()
+
+		if d.Name != "stock" || !slices.Equal(d.Path, []string{"stock"}) || d.Usage != "inventory utility" {
+			t.Errorf("wrong description %+v", d)
+		}
+		if len(d.Flags) != 2 {
+			t.Fatalf("wrong flags %+v", d.Flags)
+		}
+		if f := d.Flags[0]; f.Name != "count" || f.Type != "items" || f.Usage != "number of items" || f.Default != "1" {
+			t.Errorf("wrong flag %+v", f)
+		}
+		if f := d.Flags[1]; f.Name != "sku" || f.Type != "value" || f.Default != "" {
+			t.Errorf("wrong flag %+v", f)
+		}
+		if len(d.Positional) != 1 {
+			t.Fatalf("wrong positional %+v", d.Positional)
+		}
+		if p := d.Positional[0]; p.Name != "bin" || p.Required || p.Default != "a" || !slices.Equal(p.Choices, []string{"a", "b"}) {
+			t.Errorf("wrong positional %+v", p)
+		}
+		if d.Subcommands != nil {
+			t.Errorf("wrong subcommands %+v", d.Subcommands)
+		}
+	})
+
+	t.Run("Section", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.UsageTemplate = template.Must(template.Must(command.UsageTemplate.Clone()).Parse(
+			`{{define "options"}}` + "\nFlags:\n" +
+				`{{range .Flags}}  --{{.Name}}{{with .Default}}={{.}}{{end}}` + "\n" + `{{end}}` +
+				`{{end}}`,
+		))
+
+		if usageString(cmd) !=
+			`Usage: stock [options] [bin]
+
+  inventory utility
+
+Flags:
+  --count=1
+  --sku
+
+Arguments:
+  bin   bin to stock (one of: a, b) (default a)
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("PrintDefaults", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.UsageTemplate = template.Must(template.Must(command.UsageTemplate.Clone()).Parse(
+			`{{define "flag"}}  --{{.Name}}{{with .Default}}={{.}}{{end}}` + "\n" + `{{end}}`,
+		))
+		buf := new(bytes.Buffer)
+		cmd.SetOutput(buf)
+		cmd.PrintDefaults()
+
+		if buf.String() != "  --count=1\n  --sku\n" {
+			t.Errorf("wrong defaults:\n%v", buf.String())
+		}
+		if !strings.Contains(usageString(cmd), "\nOptions:\n"+buf.String()) {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Global", func(t *testing.T) {
+		original := command.UsageTemplate
+		t.Cleanup(func() { command.UsageTemplate = original })
+
+		command.UsageTemplate = template.Must(template.Must(original.Clone()).Parse(
+			`{{define "synopsis"}}{{join " " .Path | printf "%s ..."}}` + "\n\n" + `{{end}}`,
+		))
+
+		cmd := buildCommand()
+
+		if !strings.HasPrefix(usageString(cmd), "stock ...\n\n  inventory utility\n") {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Inherited", func(t *testing.T) {
+		var usage string
+		cmd := command.New("stock", "inventory utility", flag.ContinueOnError)
+		cmd.UsageTemplate = template.Must(template.New("usage").Parse(`{{define "usage"}}{{.Name}}: {{.Usage}}{{end}}`))
+		cmd.Subcommand("count", "count items", func(cmd command.Bound) {
+			usage = usageString(cmd.Command)
+		})
+		cmd.Parse([]string{"count"})
+
+		if usage != "stock count: count items" {
+			t.Errorf("wrong usage %q", usage)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.UsageTemplate = template.Must(template.New("usage").Parse(`{{define "usage"}}{{.Missing}}{{end}}`))
+
+		if !strings.Contains(usageString(cmd), "can't evaluate field Missing") {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+}
+
+func TestCommandExamples(t *testing.T) {
+	buildCommand := func() *command.Command {
+		cmd := command.New("stock", "inventory utility", flag.ContinueOnError)
+		cmd.PositionalString("item", nil, "item to stock")
+		cmd.Example("stock widget", "stock a widget")
+		cmd.Example("stock gadget", "")
+		cmd.Section("Environment", "STOCK_DB  path to the inventory database")
+		cmd.Section("Exit Status", "0 on success\n1 when the item is unknown")
+		return cmd
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: stock <item>
+
+  inventory utility
+
+Arguments:
+  item  item to stock
+
+Examples:
+  stock widget
+    stock a widget
+  stock gadget
+
+Environment:
+  STOCK_DB  path to the inventory database
+
+Exit Status:
+  0 on success
+  1 when the item is unknown
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Describe", func(t *testing.T) {
+		d := buildCommand().Describe()
+
+		if !slices.Equal(d.Examples, []command.Example{{"stock widget", "stock a widget"}, {"stock gadget", ""}}) {
+			t.Errorf("wrong examples %v", d.Examples)
+		}
+		if len(d.Sections) != 2 || d.Sections[0].Title != "Environment" || d.Sections[1].Title != "Exit Status" {
+			t.Errorf("wrong sections %v", d.Sections)
+		}
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.Wrap = true
+		cmd.Width = 24
+
+		if !strings.HasSuffix(usageString(cmd),
+			`Examples:
+  stock widget
+    stock a widget
+  stock gadget
+
+Environment:
+  STOCK_DB path to the
+  inventory database
+
+Exit Status:
+  0 on success
+  1 when the item is
+  unknown
+`) {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+}
+
+func TestCommandWalk(t *testing.T) {
+	var called bool
+	cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+	cmd.Help = true
+	cmd.Define("fleet", "manage the fleet", func(cmd *command.Command) {
+		cmd.Define("list", "list all trucks", func(cmd *command.Command) {
+			cmd.Bool("all", false, "include retired trucks")
+		}, func(cmd command.Bound) {
+			called = true
+		})
+	}, func(cmd command.Bound) {
+		called = true
+	})
+	cmd.Subcommand("buy", "buy a stock truck", func(command.Bound) { called = true })
+
+	var names []string
+	var flags int
+	err := cmd.Walk(func(c *command.Command) error {
+		names = append(names, c.Name())
+		if c.HasFlags() {
+			flags++
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("walk failed with %v", err)
+	}
+	if expected := []string{"trucker", "trucker buy", "trucker fleet", "trucker fleet list"}; !slices.Equal(names, expected) {
+		t.Errorf("wrong commands %v, expected %v", names, expected)
+	}
+	if flags != 1 {
+		t.Errorf("wrong number of commands with flags %v, expected %v", flags, 1)
+	}
+	if called {
+		t.Error("called handler")
+	}
+
+	stop := errors.New("stop")
+	names = nil
+	err = cmd.Walk(func(c *command.Command) error {
+		names = append(names, c.Name())
+		if c.Name() == "trucker buy" {
+			return stop
+		}
+		return nil
+	})
+
+	if err != stop {
+		t.Errorf("wrong error %v, expected %v", err, stop)
+	}
+	if expected := []string{"trucker", "trucker buy"}; !slices.Equal(names, expected) {
+		t.Errorf("wrong commands %v, expected %v", names, expected)
+	}
+}
+
+func TestCommandCollectErrors(t *testing.T) {
+	buildCommand := func() *command.Command {
+		cmd := command.New("trucker", "make a ford truck", flag.ContinueOnError)
+		cmd.CollectErrors = true
+		cmd.Int("doors", 2, "number of doors", check.AtMost(4))
+		cmd.Bool("diesel", false, "diesel engine")
+		cmd.PositionalUint("yr", nil, "model year", check.AtMost[uint](2024))
+		cmd.PositionalInt("f", nil, "model num", check.AtLeast(150))
+		return cmd
+	}
+
+	t.Run("AllErrors", func(t *testing.T) {
+		cmd := buildCommand()
+		buf := new(bytes.Buffer)
+		cmd.SetOutput(buf)
+		err := cmd.Parse([]string{"-doors", "6", "-wheels=18", "-diesel=maybe", "2048"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+
+		expected := `invalid value "6" for flag -doors: must be at most 4
+flag provided but not defined: -wheels
+invalid boolean value "maybe" for -diesel: parse error
+invalid value "2048" for argument yr: must be at most 2024
+missing argument for <f>`
+		if err.Error() != expected {
+			t.Errorf("wrong error:\n%v", err)
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatalf("error %T is not joined", err)
+		}
+		errs := joined.Unwrap()
+		if len(errs) != 5 {
+			t.Fatalf("wrong number of errors %v, expected %v", len(errs), 5)
+		}
+		for _, err := range errs[:3] {
+			if _, ok := err.(*flag.FlagError); !ok {
+				t.Errorf("wrong type %T for error %v", err, err)
+			}
+		}
+		for _, err := range errs[3:] {
+			if _, ok := err.(*command.ArgumentError); !ok {
+				t.Errorf("wrong type %T for error %v", err, err)
+			}
+		}
+		if name := errs[0].(*flag.FlagError).Name; name != "doors" {
+			t.Errorf("wrong flag name %v, expected %v", name, "doors")
+		}
+		if name := errs[4].(*command.ArgumentError).Name; name != "f" {
+			t.Errorf("wrong argument name %v, expected %v", name, "f")
+		}
+
+		if output := buf.String(); strings.Count(output, "Usage:") != 1 || !strings.HasPrefix(output, expected+"\nUsage:") {
+			t.Errorf("wrong output:\n%v", output)
+		}
+	})
+
+	t.Run("SingleError", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.SetOutput(io.Discard)
+		err := cmd.Parse([]string{"2019", "50"})
+
+		var argErr *command.ArgumentError
+		if !errors.As(err, &argErr) {
+			t.Fatalf("wrong error %#v", err)
+		}
+		if err.Error() != `invalid value "50" for argument f: must be at least 150` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("BadSyntax", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.SetOutput(io.Discard)
+		err := cmd.Parse([]string{"---doors", "-doors=9", "2019", "150"})
+
+		expected := `bad flag syntax: ---doors
+invalid value "9" for flag -doors: must be at most 4`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("wrong error %v", err)
+		}
+		if name := err.(interface{ Unwrap() []error }).Unwrap()[0].(*flag.FlagError).Name; name != "---doors" {
+			t.Errorf("wrong flag name %v, expected %v", name, "---doors")
+		}
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.SetOutput(io.Discard)
+		err := cmd.Parse([]string{"-wheels", "-h", "2048"})
+
+		if err != flag.ErrHelp {
+			t.Errorf("wrong error %v, expected %v", err, flag.ErrHelp)
+		}
+	})
+
+	t.Run("Subcommand", func(t *testing.T) {
+		var called bool
+		var childErr error
+		cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+		cmd.CollectErrors = true
+		cmd.SetOutput(io.Discard)
+		cmd.Bool("v", false, "verbose")
+		cmd.Subcommand("buy", "buy a stock truck", func(cmd command.Bound) {
+			called = true
+			cmd.SetOutput(io.Discard)
+			cmd.Int("doors", 2, "number of doors", check.AtMost(4))
+			cmd.PositionalInt("f", nil, "model num", check.AtLeast(150))
+			childErr = cmd.Parse()
+		})
+
+		err := cmd.Parse([]string{"-x", "buy"})
+		if err == nil || err.Error() != "flag provided but not defined: -x" {
+			t.Errorf("wrong error %v", err)
+		}
+		if called {
+			t.Error("subcommand ran after parent failed")
+		}
+
+		cmd.Parse([]string{"buy", "-doors", "5", "50"})
+		if err := childErr; err == nil || err.Error() != "invalid value \"5\" for flag -doors: must be at most 4\ninvalid value \"50\" for argument f: must be at least 150" {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
+
+func TestCommandValidator(t *testing.T) {
+	var lo, hi int
+	var unit string
+
+	buildCommand := func() *command.Command {
+		cmd := command.New("trucker", "find trucks by price", flag.ContinueOnError)
+		cmd.IntVar(&lo, "min", 0, "minimum price")
+		cmd.IntVar(&hi, "max", 100000, "maximum price")
+		cmd.PositionalStringVar(&unit, "unit", ptr.To("usd"), "currency unit")
+		cmd.Validator(func(c *command.Command) error {
+			if hi <= lo {
+				return errors.New("-min must be less than -max")
+			}
+			return nil
+		}, func(c *command.Command) error {
+			if c.IsSet("unit") && !c.IsSet("max") {
+				return errors.New("-max is required with <unit>")
+			}
+			return nil
+		})
+		return cmd
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"-min", "500", "-max", "1000", "eur"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if !cmd.IsSet("min") || !cmd.IsSet("unit") {
+			t.Error("IsSet returned false for a given value")
+		}
+		if cmd.IsSet("undefined") {
+			t.Error("IsSet returned true for an undefined value")
+		}
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"-min", "500"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if cmd.IsSet("max") || cmd.IsSet("unit") {
+			t.Error("IsSet returned true for a default value")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cmd := buildCommand()
+		buf := new(bytes.Buffer)
+		cmd.SetOutput(buf)
+		err := cmd.Parse([]string{"-min", "500", "-max", "100"})
+
+		if err == nil || err.Error() != "-min must be less than -max" {
+			t.Errorf("wrong error %v", err)
+		}
+		if !strings.HasPrefix(buf.String(), "-min must be less than -max\nUsage: trucker") {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+	})
+
+	t.Run("NotRunOnParseError", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.SetOutput(io.Discard)
+		err := cmd.Parse([]string{"-min", "x", "-max", "-1"})
+
+		if err == nil || err.Error() != `invalid value "x" for flag -min: parse error` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("CollectErrors", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.CollectErrors = true
+		cmd.SetOutput(io.Discard)
+		err := cmd.Parse([]string{"-min", "200000", "eur"})
+
+		if err == nil || err.Error() != "-min must be less than -max\n-max is required with <unit>" {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("BeforeSubcommand", func(t *testing.T) {
+		var called bool
+		cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		dry := cmd.Bool("dry-run", false, "do nothing")
+		cmd.Validator(func(c *command.Command) error {
+			if *dry {
+				return errors.New("dry run is not supported")
+			}
+			return nil
+		})
+		cmd.Subcommand("buy", "buy a stock truck", func(command.Bound) { called = true })
+
+		err := cmd.Parse([]string{"-dry-run", "buy"})
+		if err == nil || err.Error() != "dry run is not supported" {
+			t.Errorf("wrong error %v", err)
+		}
+		if called {
+			t.Error("subcommand ran after validation failed")
+		}
+	})
+}
+
+func TestCommandHooks(t *testing.T) {
+	var calls []string
+	var out io.Writer
+	hook := func(name string) command.HookFunc {
+		return func(c *command.Command) error {
+			calls = append(calls, name+" "+c.Name())
+			return nil
+		}
+	}
+	fail := func(c *command.Command) error {
+		calls = append(calls, "fail "+c.Name())
+		return errors.New("no trucks available")
+	}
+
+	buildCommand := func(before ...command.HookFunc) *command.Command {
+		calls, out = nil, io.Discard
+		cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+		cmd.SetOutput(out)
+		cmd.BeforeAll(hook("root-before-all"))
+		cmd.AfterAll(hook("root-after-all"))
+		cmd.Before(hook("root-before"))
+		cmd.After(hook("root-after"))
+		cmd.Define("fleet", "manage the fleet", func(cmd *command.Command) {
+			cmd.SetOutput(out)
+			cmd.BeforeAll(hook("fleet-before-all"))
+			cmd.AfterAll(hook("fleet-after-all"))
+			cmd.Before(before...)
+			cmd.Before(hook("fleet-before"))
+			cmd.After(hook("fleet-after"))
+			cmd.Subcommand("list", "list all trucks", func(cmd command.Bound) {
+				cmd.SetOutput(out)
+				if cmd.Parse() == nil {
+					calls = append(calls, "run "+cmd.Name())
+				}
+			})
+			cmd.Subcommand("count", "count the trucks", func(cmd command.Bound) {
+				calls = append(calls, "run "+cmd.Name())
+			})
+		}, nil)
+		cmd.Subcommand("buy", "buy a stock truck", func(cmd command.Bound) {
+			cmd.SetOutput(out)
+			cmd.Int("doors", 2, "number of doors", check.AtMost(4))
+			if cmd.Parse() == nil {
+				calls = append(calls, "run "+cmd.Name())
+			}
+		})
+		return cmd
+	}
+
+	t.Run("Order", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"fleet", "list"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		expected := []string{
+			"root-before trucker fleet",
+			"root-before-all trucker fleet list",
+			"fleet-before-all trucker fleet list",
+			"fleet-before trucker fleet list",
+			"run trucker fleet list",
+			"fleet-after trucker fleet list",
+			"fleet-after-all trucker fleet list",
+			"root-after-all trucker fleet list",
+			"root-after trucker fleet",
+		}
+		if !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls:\n%v\nexpected:\n%v", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("Leaf", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"buy", "-doors", "4"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		expected := []string{
+			"root-before-all trucker buy",
+			"root-before trucker buy",
+			"run trucker buy",
+			"root-after trucker buy",
+			"root-after-all trucker buy",
+		}
+		if !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls:\n%v\nexpected:\n%v", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.Parse([]string{"buy", "-doors", "5"})
+
+		expected := []string{
+			"root-before-all trucker buy",
+			"root-before trucker buy",
+			"root-after trucker buy",
+			"root-after-all trucker buy",
+		}
+		if !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls:\n%v\nexpected:\n%v", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("NoParse", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"fleet", "count"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		expected := []string{
+			"root-before trucker fleet",
+			"root-before-all trucker fleet count",
+			"fleet-before-all trucker fleet count",
+			"fleet-before trucker fleet count",
+			"run trucker fleet count",
+			"fleet-after trucker fleet count",
+			"fleet-after-all trucker fleet count",
+			"root-after-all trucker fleet count",
+			"root-after trucker fleet",
+		}
+		if !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls:\n%v\nexpected:\n%v", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		cmd := buildCommand(fail)
+		err := cmd.Parse([]string{"fleet", "list"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		expected := []string{
+			"root-before trucker fleet",
+			"root-before-all trucker fleet list",
+			"fleet-before-all trucker fleet list",
+			"fail trucker fleet list",
+			"root-after trucker fleet",
+		}
+		if !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls:\n%v\nexpected:\n%v", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("AfterError", func(t *testing.T) {
+		cmd := buildCommand()
+		buf := new(bytes.Buffer)
+		out = buf
+		cmd.After(fail)
+		err := cmd.Parse([]string{"buy"})
+
+		if err == nil || err.Error() != "no trucks available" {
+			t.Errorf("wrong error %v", err)
+		}
+		if buf.String() != "no trucks available\n" {
+			t.Errorf("wrong output:\n%v", buf.String())
+		}
+		if calls[len(calls)-1] != "root-after-all trucker buy" {
+			t.Errorf("after hooks stopped at error: %v", calls)
+		}
+	})
+}
+
+func TestCommandMiddleware(t *testing.T) {
+	var calls []string
+	var out *bytes.Buffer
+	var fleetErr error
+	trace := func(name string) command.Middleware {
+		return func(next command.HandlerFunc) command.HandlerFunc {
+			return func(b command.Bound) {
+				calls = append(calls, name+" "+b.Name())
+				next(b)
+			}
+		}
+	}
+
+	buildCommand := func(middleware ...command.Middleware) *command.Command {
+		calls, out, fleetErr = nil, new(bytes.Buffer), nil
+		cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+		cmd.SetOutput(out)
+		cmd.Use(trace("outer"), trace("inner"))
+		cmd.Use(middleware...)
+		cmd.Define("fleet", "manage the fleet", func(cmd *command.Command) {
+			cmd.SetOutput(out)
+			cmd.Use(trace("fleet"))
+			cmd.Subcommand("drain", "drain a truck", func(cmd command.Bound) {
+				cmd.SetOutput(out)
+				cmd.Int("doors", 2, "number of doors")
+				cmd.Secret("token", "", "api token")
+				cmd.PositionalString("truck", nil, "truck to drain")
+				if cmd.Parse() == nil {
+					calls = append(calls, "run "+cmd.Name())
+				}
+			})
+			cmd.Subcommand("crash", "crash a truck", func(cmd command.Bound) {
+				cmd.SetOutput(out)
+				cmd.Parse()
+				panic("truck crashed")
+			})
+		}, func(cmd command.Bound) {
+			fleetErr = cmd.Parse()
+		})
+		return cmd
+	}
+
+	t.Run("Order", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"fleet", "drain", "t1"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		expected := []string{
+			"outer trucker fleet",
+			"inner trucker fleet",
+			"outer trucker fleet drain",
+			"inner trucker fleet drain",
+			"fleet trucker fleet drain",
+			"run trucker fleet drain",
+		}
+		if !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls:\n%v\nexpected:\n%v", strings.Join(calls, "\n"), strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("Recover", func(t *testing.T) {
+		cmd := buildCommand(command.Recover)
+		err := cmd.Parse([]string{"fleet", "crash"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if err := fleetErr; err == nil || err.Error() != "panic: truck crashed" {
+			t.Errorf("wrong error %v", err)
+		}
+		if out.String() != "panic: truck crashed\n" {
+			t.Errorf("wrong output:\n%v", out.String())
+		}
+	})
+
+	t.Run("Timing", func(t *testing.T) {
+		var timed []string
+		cmd := buildCommand(command.Timing(func(c *command.Command, d time.Duration) {
+			timed = append(timed, c.Name())
+			if d <= 0 {
+				t.Errorf("wrong duration %v", d)
+			}
+		}))
+		cmd.Parse([]string{"fleet", "drain", "t1"})
+
+		if expected := []string{"trucker fleet drain"}; !slices.Equal(timed, expected) {
+			t.Errorf("wrong timed commands %v, expected %v", timed, expected)
+		}
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		log := new(bytes.Buffer)
+		logger := slog.New(slog.NewTextHandler(log, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		cmd := buildCommand(command.Audit(logger))
+		err := cmd.Parse([]string{"fleet", "drain", "-token", "tok_123", "-doors=4", "t1", "now"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if expected := `level=INFO msg=command command="trucker fleet drain" args="[-doors=4 -token=` + value.Redacted + ` \"t1\" now]"` + "\n"; log.String() != expected {
+			t.Errorf("wrong log:\n%v\nexpected:\n%v", log.String(), expected)
+		}
+	})
+
+	t.Run("Gate", func(t *testing.T) {
+		cmd := buildCommand(command.Gate(func(c *command.Command) bool {
+			return c.Name() != "trucker fleet drain"
+		}))
+		cmd.Parse([]string{"fleet", "drain", "t1"})
+
+		if err := fleetErr; err == nil || err.Error() != "command not enabled: trucker fleet drain" {
+			t.Errorf("wrong error %v", err)
+		}
+		if slices.Contains(calls, "run trucker fleet drain") {
+			t.Error("gated command ran")
+		}
+	})
+
+	t.Run("Walk", func(t *testing.T) {
+		cmd := buildCommand(command.Recover)
+		var names []string
+		cmd.Walk(func(c *command.Command) error {
+			names = append(names, c.Name())
+			return nil
+		})
+
+		if len(calls) != 0 {
+			t.Errorf("middleware called during walk: %v", calls)
+		}
+		if expected := []string{"trucker", "trucker fleet", "trucker fleet crash", "trucker fleet drain"}; !slices.Equal(names, expected) {
+			t.Errorf("wrong commands %v, expected %v", names, expected)
+		}
+	})
+}
+
+func TestCommandPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	dir, other := t.TempDir(), t.TempDir()
+	out := filepath.Join(t.TempDir(), "out")
+	t.Setenv("TRUCKER_OUT", out)
+	t.Setenv("PATH", dir+string(filepath.ListSeparator)+other)
+
+	script := func(dir, name, body string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	script(dir, "trucker-paint", `echo "$COMMAND_NAME|$COMMAND_PLUGIN|$*" > "$TRUCKER_OUT"`)
+	script(dir, "trucker-crush", `exit 3`)
+	script(dir, "trucker-buy", `exit 1`)
+	script(dir, "trucker-fleet-scrap", `echo "$COMMAND_NAME|$COMMAND_PLUGIN|$*" > "$TRUCKER_OUT"`)
+	script(other, "trucker-paint", `exit 1`)
+	script(other, "trucker-wash", `exit 0`)
+	if err := os.WriteFile(filepath.Join(dir, "trucker-notes"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buyCalled bool
+	buildCommand := func() *command.Command {
+		buyCalled = false
+		cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+		cmd.SetOutput(io.Discard)
+		cmd.Plugins = true
+		cmd.Subcommand("buy", "buy a stock truck", func(command.Bound) { buyCalled = true })
+		cmd.Subcommand("fleet", "manage the fleet", func(cmd command.Bound) {
+			cmd.SetOutput(io.Discard)
+			cmd.Subcommand("list", "list all trucks", func(command.Bound) {})
+			cmd.Parse()
+		})
+		return cmd
+	}
+
+	readOut := func() string {
+		b, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	t.Run("Info", func(t *testing.T) {
+		cmd := buildCommand()
+
+		if usageString(cmd) !=
+			`Usage: trucker <command>
+
+  truck utility
+
+Commands:
+  buy    buy a stock truck
+  fleet  manage the fleet
+
+Plugins:
+  crush  `+filepath.Join(dir, "trucker-crush")+`
+  paint  `+filepath.Join(dir, "trucker-paint")+`
+  wash   `+filepath.Join(other, "trucker-wash")+`
+` {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+
+		buf := new(bytes.Buffer)
+		cmd.SetOutput(buf)
+		cmd.PrintSubcommands()
+		if !strings.Contains(buf.String(), "\nPlugins:\n  crush ") {
+			t.Errorf("wrong subcommands:\n%v", buf.String())
+		}
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"paint", "-color", "red", "cab"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if s := readOut(); s != "trucker|paint|-color red cab\n" {
+			t.Errorf("wrong plugin output %q", s)
+		}
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"fleet", "scrap", "t1"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if s := readOut(); s != "trucker fleet|scrap|t1\n" {
+			t.Errorf("wrong plugin output %q", s)
+		}
+	})
+
+	t.Run("ExitStatus", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"crush"})
+
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) {
+			t.Fatalf("wrong error %v", err)
+		}
+		if exit.ExitCode() != 3 {
+			t.Errorf("wrong exit status %v, expected %v", exit.ExitCode(), 3)
+		}
+	})
+
+	t.Run("SubcommandPrecedence", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"buy"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if !buyCalled {
+			t.Error("subcommand was not called")
+		}
+	})
+
+	t.Run("NotExecutable", func(t *testing.T) {
+		cmd := buildCommand()
+		err := cmd.Parse([]string{"notes"})
+
+		if err == nil || err.Error() != "unknown command: notes" {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("PluginDirs", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.PluginDirs = []string{other}
+		err := cmd.Parse([]string{"paint"})
+
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+			t.Errorf("wrong error %v", err)
+		}
+		if c := cmd.Complete([]string{""}); !slices.Equal(c, []string{"buy", "fleet", "paint", "wash"}) {
+			t.Errorf("wrong completions %v", c)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd := buildCommand()
+		cmd.Plugins = false
+		err := cmd.Parse([]string{"paint"})
+
+		if err == nil || err.Error() != "unknown command: paint" {
+			t.Errorf("wrong error %v", err)
+		}
+		if c := cmd.Complete([]string{""}); !slices.Equal(c, []string{"buy", "fleet"}) {
+			t.Errorf("wrong completions %v", c)
+		}
+	})
+
+	t.Run("NotDescribed", func(t *testing.T) {
+		cmd := buildCommand()
+
+		if d := cmd.Describe(); d.Plugins != nil {
+			t.Errorf("wrong plugins %v", d.Plugins)
+		}
+	})
+
+	t.Run("Streams", func(t *testing.T) {
+		streams := t.TempDir()
+		script(streams, "trucker-echo", `read line; echo "out $line"; echo "err $line" >&2`)
+		script(streams, "trucker-warn", `echo "low fuel" >&2`)
+
+		cmd := buildCommand()
+		cmd.PluginDirs = []string{streams}
+		buf := new(bytes.Buffer)
+		cmd.SetOutput(buf)
+
+		if err := cmd.Parse([]string{"warn"}); err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if buf.String() != "low fuel\n" {
+			t.Errorf("wrong output %q", buf.String())
+		}
+
+		cmd = buildCommand()
+		cmd.PluginDirs = []string{streams}
+		shellOut := new(bytes.Buffer)
+		cmd.SetShell(command.Shell{Prompt: "> ", In: strings.NewReader("echo\nbig rig\n"), Out: shellOut})
+
+		if err := cmd.Parse([]string{"shell"}); err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if !strings.Contains(shellOut.String(), "out big rig\nerr big rig\n") {
+			t.Errorf("wrong shell output %q", shellOut.String())
+		}
+	})
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+		err      string
+	}{
+		{``, nil, ""},
+		{`  buy  big rig `, []string{"buy", "big", "rig"}, ""},
+		{`buy 'big rig' "red \"cab\"" \$x`, []string{"buy", "big rig", `red "cab"`, "$x"}, ""},
+		{`a'b'"c"\ d`, []string{"abc d"}, ""},
+		{`'it\s' "a\b" ''`, []string{`it\s`, `a\b`, ""}, ""},
+		{`buy 'big`, nil, "unterminated ' quote"},
+		{`buy "big`, nil, `unterminated " quote`},
+		{`buy \`, nil, "trailing backslash"},
+	}
+
+	for _, test := range tests {
+		args, err := command.Split(test.line)
+
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("wrong error %v for %q, expected %v", err, test.line, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("split %q failed with %v", test.line, err)
+		}
+		if !slices.Equal(args, test.expected) {
+			t.Errorf("wrong args %q for %q, expected %q", args, test.line, test.expected)
+		}
+	}
+}
+
+func TestCommandShell(t *testing.T) {
+	var calls []string
+
+	buildCommand := func(shell command.Shell) *command.Command {
+		calls = nil
+		cmd := command.New("trucker", "truck utility", flag.ExitOnError)
+		cmd.Help = true
+		cmd.SetShell(shell)
+		var doors *int
+		var color, model *string
+		cmd.Define("buy", "buy a stock truck", func(cmd *command.Command) {
+			doors = cmd.Int("doors", 2, "number of doors", check.AtMost(4))
+			color = cmd.String("color", "white", "paint color", check.OneOf("red", "blue"))
+			model = cmd.PositionalString("model", nil, "truck model")
+		}, func(cmd command.Bound) {
+			if cmd.Parse() == nil {
+				calls = append(calls, fmt.Sprintf("buy %v %v %v", *doors, *color, *model))
+			}
+		})
+		cmd.Define("fleet", "manage the fleet", func(cmd *command.Command) {
+			cmd.Define("list", "list all trucks", func(cmd *command.Command) {
+				cmd.Bool("all", false, "include retired trucks")
+			}, func(cmd command.Bound) {
+				if cmd.Parse() == nil {
+					calls = append(calls, "fleet list")
+				}
+			})
+		}, nil)
+		return cmd
+	}
+
+	t.Run("Run", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		cmd := buildCommand(command.Shell{
+			In: strings.NewReader(`buy -doors 4 'big rig'
+buy -doors 9 x
+
+bogus
+fleet list
+help fleet
+buy 'unterminated
+history
+exit
+buy -doors 1 never
+`),
+			Out: out,
+		})
+		err := cmd.Parse([]string{"shell"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if expected := []string{"buy 4 white big rig", "fleet list"}; !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls %q, expected %q", calls, expected)
+		}
+
+		output := out.String()
+		for _, expected := range []string{
+			"trucker> ",
+			"invalid value \"9\" for flag -doors: must be at most 4\nUsage: trucker buy [options] <model>",
+			"trucker> unknown command: bogus\n",
+			"Usage: trucker fleet <command>",
+			"trucker> unterminated ' quote\n",
+			`   1  buy -doors 4 'big rig'
+   2  buy -doors 9 x
+   3  bogus
+   4  fleet list
+   5  help fleet
+   6  history
+`,
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("output missing %q:\n%v", expected, output)
+			}
+		}
+		if strings.HasSuffix(output, "trucker> trucker> ") {
+			t.Errorf("shell continued after exit:\n%v", output)
+		}
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		cmd := buildCommand(command.Shell{In: strings.NewReader("sell -count x\nsell -h\nsell -count 2\n"), Out: out})
+		cmd.Use(command.Recover)
+		cmd.Define("sell", "sell trucks", func(cmd *command.Command) {
+			cmd.Int("count", 1, "number of trucks")
+		}, func(cmd command.Bound) {
+			cmd.Parse()
+			calls = append(calls, "sell "+cmd.Lookup("count").Value.String())
+		})
+		err := cmd.Parse([]string{"shell"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if expected := []string{"sell 2"}; !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls %q, expected %q", calls, expected)
+		}
+		if output := out.String(); !strings.Contains(output, `invalid value "x" for flag -count`) || strings.Contains(output, "panic") {
+			t.Errorf("wrong output:\n%v", output)
+		}
+	})
+
+	t.Run("EndOfInput", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		cmd := buildCommand(command.Shell{In: strings.NewReader("fleet list\n"), Out: out, Prompt: "$ "})
+		err := cmd.Parse([]string{"shell"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if out.String() != "$ $ \n" {
+			t.Errorf("wrong output %q", out.String())
+		}
+	})
+
+	t.Run("Info", func(t *testing.T) {
+		cmd := buildCommand(command.Shell{})
+
+		if !strings.Contains(usageString(cmd), "  shell  start an interactive shell\n") {
+			t.Errorf("wrong usage:\n%v", usageString(cmd))
+		}
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		cmd := buildCommand(command.Shell{
+			In: strings.NewReader(
+				"bu\t-do\t4 -color=\tr\tbig\x7f\x7f\x7fsmall\r" +
+					"\t\r" +
+					"\x1b[A\x1b[A\r" +
+					"nothing\x15\x1b[A\x1b[B\r" +
+					"\x04"),
+			Out:  out,
+			Edit: true,
+		})
+		err := cmd.Parse([]string{"shell"})
+
+		if err != nil {
+			t.Fatalf("parse failed with %v", err)
+		}
+		if expected := []string{"buy 4 red small", "buy 4 red small"}; !slices.Equal(calls, expected) {
+			t.Errorf("wrong calls %q, expected %q", calls, expected)
+		}
+		if output := out.String(); !strings.Contains(output, "\n-color=blue  -color=red\n") ||
+			!strings.Contains(output, "\nbuy  fleet  help  shell\n") {
+			t.Errorf("wrong output %q", output)
+		}
+	})
+}
+
+func TestCommandComplete(t *testing.T) {
+	levels := value.Enum[string]{
+		Choices: []value.Choice[string]{{Name: "low", Value: "L"}, {Name: "high", Value: "H"}},
+	}
+
+	cmd := command.New("trucker", "truck utility", flag.ContinueOnError)
+	cmd.Help = true
+	cmd.SetVersion(command.Version{Info: command.VersionInfo{Version: "1.0"}})
+	cmd.Bool("v", false, "verbose")
+	cmd.Define("buy", "buy a stock truck", func(cmd *command.Command) {
+		cmd.Int("doors", 2, "number of doors")
+		cmd.Bool("diesel", false, "diesel engine")
+		cmd.String("color", "white", "paint color", check.OneOf("red", "blue"))
+		cmd.PositionalString("model", nil, "truck model")
+		command.PositionalEnum(cmd, "level", ptr.To(""), "trim level", levels)
+	}, func(command.Bound) {
+		t.Error("called handler")
+	})
+	cmd.Subcommand("bulldoze", "bulldoze a truck", func(command.Bound) {})
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{nil, []string{"bulldoze", "buy", "help", "version"}},
+		{[]string{"b"}, []string{"bulldoze", "buy"}},
+		{[]string{"-"}, []string{"-v", "-version"}},
+		{[]string{"-v", "bul"}, []string{"bulldoze"}},
+		{[]string{"buy", "-d"}, []string{"-diesel", "-doors"}},
+		{[]string{"buy", "--c"}, []string{"--color"}},
+		{[]string{"buy", "-color", ""}, []string{"blue", "red"}},
+		{[]string{"buy", "-color=r"}, []string{"-color=red"}},
+		{[]string{"buy", "-doors", "4", "-diesel", "f150", "h"}, []string{"high"}},
+		{[]string{"buy", "f150", ""}, []string{"high", "low"}},
+		{[]string{"buy", ""}, nil},
+		{[]string{"help", ""}, []string{"bulldoze", "buy", "help", "version"}},
+		{[]string{"version", "-"}, []string{"-json"}},
+		{[]string{"help", "-"}, nil},
+		{[]string{"help", "buy", ""}, nil},
+		{[]string{"sell", ""}, nil},
+	}
+
+	for _, test := range tests {
+		if candidates := cmd.Complete(test.args); !slices.Equal(candidates, test.expected) {
+			t.Errorf("wrong candidates %q for %q, expected %q", candidates, test.args, test.expected)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"slices"
+	"strings"
+)
+
+// Complete returns the candidates for completing the last of the given arguments, which may be empty,
+// following those before it, as given to this command. Candidates are drawn from the names of subcommands
+// and plugins, the names of flags, and the choices of flags and positional parameters, including those
+// accepted by their checks. Following the help subcommand, candidates are drawn from the names of
+// subcommands alone.
+//
+// Subcommands are described as by [Command.Walk], without calling their handlers.
+// Hidden flags and subcommands are never candidates, though they are followed when given.
+// Nothing is completed following a plugin, whose arguments are unknown.
+func (c *Command) Complete(args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	cmd := c
+	d := cmd.Describe()
+	var pending *FlagDescription
+	positional := 0
+	helping := false
+
+	for _, arg := range args[:len(args)-1] {
+		switch {
+		case pending != nil:
+			pending = nil
+		case !helping && 1 < len(arg) && arg[0] == '-':
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if f := findFlag(d, name); f != nil && f.Type != "" && !hasValue {
+				pending = f
+			}
+		case d.Subcommands != nil:
+			if _, defined := cmd.subcommands[arg]; !defined && !helping && cmd.Help && arg == helpCommand {
+				helping = true
+				continue
+			}
+			child, ok := cmd.describe(arg)
+			if !ok {
+				return nil
+			}
+			cmd = child
+			d, positional = cmd.Describe(), 0
+		default:
+			positional++
+		}
+	}
+
+	last := args[len(args)-1]
+	var candidates []string
+
+	switch {
+	case helping:
+		for _, s := range d.Visible().Subcommands {
+			candidates = append(candidates, s.Name)
+		}
+	case pending != nil:
+		candidates = choices(pending.Choices, pending.Constraints)
+	case strings.HasPrefix(last, "-"):
+		trimmed := strings.TrimLeft(last, "-")
+		dashes := last[:len(last)-len(trimmed)]
+		if name, _, ok := strings.Cut(trimmed, "="); ok {
+			if f := findFlag(d, name); f != nil {
+				for _, choice := range choices(f.Choices, f.Constraints) {
+					candidates = append(candidates, dashes+name+"="+choice)
+				}
+			}
+		} else {
+			for _, f := range d.Visible().Flags {
+				candidates = append(candidates, dashes+f.Name)
+			}
+		}
+	case d.Subcommands != nil:
+		for _, s := range slices.Concat(d.Visible().Subcommands, cmd.describePlugins()) {
+			candidates = append(candidates, s.Name)
+		}
+	case positional < len(d.Positional):
+		p := d.Positional[positional]
+		candidates = choices(p.Choices, p.Constraints)
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, last) {
+			matches = append(matches, candidate)
+		}
+	}
+	slices.Sort(matches)
+	return matches
+}
+
+func findFlag(d Description, name string) *FlagDescription {
+	for i := range d.Flags {
+		if d.Flags[i].Name == name {
+			return &d.Flags[i]
+		}
+	}
+	return nil
+}
+
+// choices returns the accepted values, together with any accepted by constraints.
+func choices(choices []string, constraints []value.Constraint) []string {
+	for _, c := range constraints {
+		choices = append(choices[:len(choices):len(choices)], c.Choices...)
+	}
+	return choices
+}
@@ -0,0 +1,302 @@
+package doc
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/command"
+	"github.com/michaeljpetter/command/flag"
+	"github.com/michaeljpetter/command/internal"
+	"github.com/michaeljpetter/command/value"
+	"slices"
+	"strconv"
+)
+
+// Build creates the command described by the spec, as read by [ReadJSON],
+// such that [Describe] returns the same spec. Each subcommand is declared by [command.Command.Define]
+// without a handler, so it is simply parsed, and built-in subcommands are enabled as described.
+//
+// Values are built by their Go type, which must be bool, string, any size of int, uint, or float,
+// or [time.Duration], and are parsed as by the plain flag of that type.
+// Constraints with bounds or choices are enforced by checks, while any others are only described.
+// The default of a secret is redacted, and so is not restored.
+func Build(spec Spec, errorHandling flag.ErrorHandling) (*command.Command, error) {
+	if spec.Command == nil {
+		return nil, errors.New("missing command")
+	}
+
+	define, err := compile(spec.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	c := command.New(spec.Command.Name, spec.Command.Usage, errorHandling)
+	define(c)
+	return c, nil
+}
+
+// compile returns a function defining the command described by the spec,
+// having checked that everything it describes can be defined.
+func compile(spec *CommandSpec) (command.DefineFunc, error) {
+	var defines []command.DefineFunc
+	builtin := make(map[string]bool)
+
+	for _, s := range spec.Subcommands {
+		if !s.Builtin {
+			continue
+		}
+
+		builtin[s.Name] = true
+		switch s.Name {
+		case "help":
+			defines = append(defines, func(c *command.Command) { c.Help = true })
+		case "version":
+			defines = append(defines, func(c *command.Command) { c.SetVersion(command.Version{}) })
+		case "shell":
+			defines = append(defines, func(c *command.Command) { c.SetShell(command.Shell{}) })
+		default:
+			return nil, fmt.Errorf("%s: unknown built-in subcommand %s", spec.Name, s.Name)
+		}
+	}
+
+	for _, f := range spec.Flags {
+		// the version flag is defined with the version subcommand
+		if f.Name == "version" && builtin["version"] {
+			continue
+		}
+
+		define, err := compileFlag(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: flag -%s: %w", spec.Name, f.Name, err)
+		}
+		defines = append(defines, define)
+	}
+
+	for i, p := range spec.Positional {
+		if 0 < i && p.Required && !spec.Positional[i-1].Required {
+			return nil, fmt.Errorf("%s: required positional parameter %s follows an optional one", spec.Name, p.Name)
+		}
+
+		define, err := compilePositional(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: positional parameter %s: %w", spec.Name, p.Name, err)
+		}
+		defines = append(defines, define)
+	}
+
+	for _, s := range spec.Subcommands {
+		if s.Builtin {
+			continue
+		}
+		if spec.Positional != nil {
+			return nil, fmt.Errorf("%s: subcommands and positional parameters are mutually exclusive", spec.Name)
+		}
+
+		define, err := compile(s)
+		if err != nil {
+			return nil, err
+		}
+		name, usage, hidden := s.Name, s.Usage, s.Hidden
+		defines = append(defines, func(c *command.Command) {
+			c.Define(name, usage, define, nil)
+			if hidden {
+				c.HideSubcommand(name)
+			}
+		})
+	}
+
+	for _, e := range spec.Examples {
+		defines = append(defines, func(c *command.Command) { c.Example(e.Command, e.Usage) })
+	}
+
+	for _, s := range spec.Sections {
+		defines = append(defines, func(c *command.Command) { c.Section(s.Title, s.Text) })
+	}
+
+	return func(c *command.Command) {
+		for _, define := range defines {
+			define(c)
+		}
+	}, nil
+}
+
+func compileFlag(spec FlagSpec) (command.DefineFunc, error) {
+	var define command.DefineFunc
+
+	if spec.Type == "bool" {
+		defValue, err := strconv.ParseBool(cmp.Or(spec.Default, "false"))
+		if err != nil {
+			return nil, err
+		}
+		if spec.Required || spec.Choices != nil || spec.Constraints != nil {
+			return nil, errors.New("bool flags cannot be required or constrained")
+		}
+		define = func(c *command.Command) { c.Bool(spec.Name, defValue, spec.Usage) }
+	} else {
+		// an omitted default is the zero value
+		defValue := &spec.Default
+		if spec.Required {
+			defValue = nil
+		}
+
+		newValue, err := compileValue(spec.Type, defValue, spec.Choices, spec.Constraints, spec.Secret)
+		if err != nil {
+			return nil, err
+		}
+		define = func(c *command.Command) { c.Var(newValue(), spec.Name, spec.Usage) }
+	}
+
+	return func(c *command.Command) {
+		define(c)
+		if spec.Env != "" {
+			c.DocumentEnv(spec.Name, spec.Env)
+		}
+		if spec.Hidden {
+			c.Hide(spec.Name)
+		}
+	}, nil
+}
+
+func compilePositional(spec PositionalSpec) (command.DefineFunc, error) {
+	var defValue *string
+	if !spec.Required {
+		defValue = &spec.Default
+	}
+
+	newValue, err := compileValue(spec.Type, defValue, spec.Choices, spec.Constraints, spec.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *command.Command) { c.PositionalVar(newValue(), spec.Name, spec.Usage) }, nil
+}
+
+// compileValue returns a function creating a value of the given Go type, with the given default,
+// which is required if nil, and the checks described by the given choices and constraints.
+func compileValue(valueType string, defValue *string, choices []string, constraints []ConstraintSpec, secret bool) (func() command.Value, error) {
+	switch {
+	case secret && valueType == "string":
+		if defValue != nil {
+			defValue = new(string)
+		}
+		return compileScalar(internal.NewSecretValue, defValue, constraints)
+	case choices != nil && valueType == "string":
+		return compileEnum(defValue, choices, constraints)
+	case secret || choices != nil:
+		return nil, fmt.Errorf("unsupported secret or choices of type %s", valueType)
+	}
+
+	build, ok := values[valueType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %q", valueType)
+	}
+	return build(defValue, constraints)
+}
+
+// values maps the Go type of each value which can be built to the function compiling it.
+var values = map[string]func(defValue *string, constraints []ConstraintSpec) (func() command.Value, error){
+	"string":        compileWith(internal.NewStringValue),
+	"int":           compileWith(internal.NewIntValue),
+	"int8":          compileWith(internal.NewInt8Value),
+	"int16":         compileWith(internal.NewInt16Value),
+	"int32":         compileWith(internal.NewInt32Value),
+	"int64":         compileWith(internal.NewInt64Value),
+	"uint":          compileWith(internal.NewUintValue),
+	"uint8":         compileWith(internal.NewUint8Value),
+	"uint16":        compileWith(internal.NewUint16Value),
+	"uint32":        compileWith(internal.NewUint32Value),
+	"uint64":        compileWith(internal.NewUint64Value),
+	"float32":       compileWith(internal.NewFloat32Value),
+	"float64":       compileWith(internal.NewFloat64Value),
+	"time.Duration": compileWith(internal.NewDurationValue),
+}
+
+func compileWith[T cmp.Ordered, V command.Value](newValue func(*T, *T, ...value.CheckFunc[T]) V) func(*string, []ConstraintSpec) (func() command.Value, error) {
+	return func(defValue *string, constraints []ConstraintSpec) (func() command.Value, error) {
+		return compileScalar(newValue, defValue, constraints)
+	}
+}
+
+// compileScalar returns a function creating a value by newValue, with the parsed default and checks.
+func compileScalar[T cmp.Ordered, V command.Value](newValue func(*T, *T, ...value.CheckFunc[T]) V, defValue *string, constraints []ConstraintSpec) (func() command.Value, error) {
+	parse := func(raw string) (T, error) {
+		var parsed T
+		err := newValue(nil, &parsed).Set(raw)
+		return parsed, err
+	}
+
+	var def *T
+	if defValue != nil {
+		def = new(T)
+		if *defValue != "" {
+			parsed, err := parse(*defValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default: %w", err)
+			}
+			*def = parsed
+		}
+	}
+
+	checks, err := compileChecks(parse, constraints)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() command.Value {
+		return newValue(def, new(T), checks...)
+	}, nil
+}
+
+func compileEnum(defValue *string, choices []string, constraints []ConstraintSpec) (func() command.Value, error) {
+	enum := value.Enum[string]{}
+	for _, choice := range choices {
+		enum.Choices = append(enum.Choices, value.Choice[string]{Name: choice, Value: choice})
+	}
+
+	checks, err := compileChecks(enum.Parse, constraints)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() command.Value {
+		return internal.NewEnumValue(defValue, new(string), enum, checks...)
+	}, nil
+}
+
+// compileChecks returns a check for each constraint, enforcing any bounds or choices
+// and failing as described, and described by the constraint.
+func compileChecks[T cmp.Ordered](parse func(string) (T, error), constraints []ConstraintSpec) ([]value.CheckFunc[T], error) {
+	var checks []value.CheckFunc[T]
+
+	for _, c := range constraints {
+		min, err := parseBound(parse, c.Min)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %w", c.Description, err)
+		}
+		max, err := parseBound(parse, c.Max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %w", c.Description, err)
+		}
+
+		checks = append(checks, value.Describe(func(v T) error {
+			switch {
+			case min != nil && (v < *min || c.MinExclusive && v == *min),
+				max != nil && (*max < v || c.MaxExclusive && v == *max),
+				c.Choices != nil && !slices.Contains(c.Choices, fmt.Sprint(v)):
+				return fmt.Errorf("must be %s", c.Description)
+			}
+			return nil
+		}, value.Constraint(c)))
+	}
+
+	return checks, nil
+}
+
+// parseBound parses a bound of a constraint, returning nil if there is none.
+func parseBound[T any](parse func(string) (T, error), raw string) (*T, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	bound, err := parse(raw)
+	return &bound, err
+}
@@ -0,0 +1,273 @@
+package doc_test
+
+import (
+	"bytes"
+	stdflag "flag"
+	"github.com/michaeljpetter/command"
+	"github.com/michaeljpetter/command/check"
+	"github.com/michaeljpetter/command/doc"
+	"github.com/michaeljpetter/command/flag"
+	"github.com/michaeljpetter/ptr"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var update = stdflag.Bool("update", false, "update golden files")
+
+func buildCommand() *command.Command {
+	cmd := command.New("trucker", "truck utility\n\nmanages a fleet of trucks", flag.ContinueOnError)
+	cmd.Help = true
+	cmd.Bool("v", false, "verbose output")
+	cmd.String("depot", "main", "the `name` of the depot")
+	cmd.DocumentEnv("depot", "TRUCKER_DEPOT")
+	cmd.Bool("trace", false, "trace requests")
+	cmd.Hide("trace")
+	cmd.Section("Environment", "TRUCKER_HOME  directory holding fleet data")
+	cmd.Section("See Also", "The fleet handbook.")
+
+	cmd.Define("buy", "buy a stock truck", func(cmd *command.Command) {
+		cmd.Int("count", 1, "number of trucks", check.AtLeast(1), check.AtMost(10))
+		cmd.PositionalString("model", nil, "model to buy", check.NotBlank)
+		cmd.PositionalString("color", ptr.To("white"), "paint color")
+		cmd.Example("trucker buy -count 2 f150 red", "buy two red trucks")
+	}, nil)
+
+	cmd.Define("fleet", "manage the fleet", func(cmd *command.Command) {
+		cmd.Define("list", "list all trucks", nil, nil)
+	}, nil)
+
+	cmd.Define("admin", "administer the depot", func(cmd *command.Command) {
+		cmd.Define("reset", "reset all data", nil, nil)
+	}, nil)
+	cmd.HideSubcommand("admin")
+
+	return cmd
+}
+
+func checkGolden(t *testing.T, path string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("output does not match %s:\n%s", path, actual)
+	}
+}
+
+func TestManPages(t *testing.T) {
+	man := doc.Man{Date: "June 2024", Source: "trucker 1.0", Manual: "Trucker Manual"}
+
+	checkGoldenPages(t, filepath.Join("testdata", "man"),
+		func(dir string) error { return man.WritePages(dir, buildCommand()) },
+		[]string{"trucker-buy.1", "trucker-fleet-list.1", "trucker-fleet.1", "trucker.1"},
+	)
+}
+
+func TestManPage(t *testing.T) {
+	buf := new(bytes.Buffer)
+	man := doc.Man{Section: "8"}
+
+	if err := man.WritePage(buf, buildCommand()); err != nil {
+		t.Fatalf("write failed with %v", err)
+	}
+
+	checkGolden(t, filepath.Join("testdata", "man", "trucker.8"), buf.Bytes())
+}
+
+func checkGoldenPages(t *testing.T, golden string, write func(dir string) error, expected []string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	if err := write(dir); err != nil {
+		t.Fatalf("write failed with %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+
+		actual, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, filepath.Join(golden, entry.Name()), actual)
+	}
+
+	if !slices.Equal(names, expected) {
+		t.Errorf("wrong pages %v, expected %v", names, expected)
+	}
+}
+
+func TestMarkdownPages(t *testing.T) {
+	checkGoldenPages(t, filepath.Join("testdata", "markdown"),
+		func(dir string) error { return doc.Markdown{}.WritePages(dir, buildCommand()) },
+		[]string{"trucker-buy.md", "trucker-fleet-list.md", "trucker-fleet.md", "trucker.md"},
+	)
+}
+
+func TestMarkdownDocument(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	if err := (doc.Markdown{}).WriteDocument(buf, buildCommand()); err != nil {
+		t.Fatalf("write failed with %v", err)
+	}
+
+	checkGolden(t, filepath.Join("testdata", "markdown", "document.md"), buf.Bytes())
+}
+
+func TestHTMLPages(t *testing.T) {
+	checkGoldenPages(t, filepath.Join("testdata", "html"),
+		func(dir string) error { return doc.HTML{}.WritePages(dir, buildCommand()) },
+		[]string{"trucker-buy.html", "trucker-fleet-list.html", "trucker-fleet.html", "trucker.html"},
+	)
+}
+
+func TestHTMLDocument(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	if err := (doc.HTML{Title: "Trucker Reference"}).WriteDocument(buf, buildCommand()); err != nil {
+		t.Fatalf("write failed with %v", err)
+	}
+
+	checkGolden(t, filepath.Join("testdata", "html", "document.html"), buf.Bytes())
+}
+
+func TestJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	if err := doc.WriteJSON(buf, buildCommand()); err != nil {
+		t.Fatalf("write failed with %v", err)
+	}
+
+	checkGolden(t, filepath.Join("testdata", "json", "trucker.json"), buf.Bytes())
+
+	spec, err := doc.ReadJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("read failed with %v", err)
+	}
+
+	expected, err := doc.Describe(buildCommand())
+	if err != nil {
+		t.Fatalf("describe failed with %v", err)
+	}
+	if !reflect.DeepEqual(spec, expected) {
+		t.Errorf("wrong spec %+v, expected %+v", spec, expected)
+	}
+
+	list := spec.Command.Find("fleet", "list")
+	if list == nil || !slices.Equal(list.Path, []string{"trucker", "fleet", "list"}) {
+		t.Errorf("wrong command %+v", list)
+	}
+	if help := spec.Command.Find("help"); help == nil || !help.Builtin {
+		t.Errorf("wrong command %+v", help)
+	}
+	if missing := spec.Command.Find("sell"); missing != nil {
+		t.Errorf("wrong command %+v", missing)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	_, err := doc.ReadJSON(strings.NewReader(`{"schema": 2, "command": {"name": "trucker"}}`))
+
+	if err == nil {
+		t.Fatal("read succeeded")
+	}
+	if err.Error() != `unsupported schema version 2, expected 1` {
+		t.Errorf("wrong error %v", err)
+	}
+
+	spec, err := doc.ReadJSON(strings.NewReader(`{"schema": 1, "command": {"name": "trucker", "future": true}}`))
+
+	if err != nil {
+		t.Fatalf("read failed with %v", err)
+	}
+	if spec.Command.Name != "trucker" {
+		t.Errorf("wrong name %v", spec.Command.Name)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	if err := doc.WriteJSON(buf, buildCommand()); err != nil {
+		t.Fatalf("write failed with %v", err)
+	}
+
+	spec, err := doc.ReadJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("read failed with %v", err)
+	}
+
+	buildSpec := func(t *testing.T, path ...string) *command.Command {
+		t.Helper()
+		cmd, err := doc.Build(doc.Spec{Schema: spec.Schema, Command: spec.Command.Find(path...)}, flag.ContinueOnError)
+		if err != nil {
+			t.Fatalf("build failed with %v", err)
+		}
+		cmd.SetOutput(io.Discard)
+		return cmd
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		actual := new(bytes.Buffer)
+
+		if err := doc.WriteJSON(actual, buildSpec(t)); err != nil {
+			t.Fatalf("write failed with %v", err)
+		}
+		if actual.String() != buf.String() {
+			t.Errorf("wrong spec:\n%s", actual)
+		}
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		err := buildSpec(t).Parse([]string{"-depot", "north", "-trace", "buy", "-count", "3", "f150"})
+
+		if err != nil {
+			t.Errorf("parse failed with %v", err)
+		}
+	})
+
+	t.Run("Constraints", func(t *testing.T) {
+		err := buildSpec(t, "buy").Parse([]string{"-count", "11", "f150"})
+
+		if err == nil {
+			t.Fatal("parse succeeded")
+		}
+		if err.Error() != `invalid value "11" for flag -count: must be at most 10` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		spec := doc.Spec{Schema: doc.SchemaVersion, Command: &doc.CommandSpec{
+			Name:  "trucker",
+			Flags: []doc.FlagSpec{{Name: "home", Type: "*url.URL"}},
+		}}
+		_, err := doc.Build(spec, flag.ContinueOnError)
+
+		if err == nil {
+			t.Fatal("build succeeded")
+		}
+		if err.Error() != `trucker: flag -home: unsupported type "*url.URL"` {
+			t.Errorf("wrong error %v", err)
+		}
+	})
+}
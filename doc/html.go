@@ -0,0 +1,146 @@
+package doc
+
+import (
+	"github.com/michaeljpetter/command"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// HTML generates reference documentation in HTML.
+type HTML struct {
+	// Title is the title of each document, defaulting to the name of the command.
+	Title string
+}
+
+// WritePage writes the reference of a single command as a complete document, linking to
+// any related commands as written by [HTML.WritePages].
+func (h HTML) WritePage(w io.Writer, c *command.Command) error {
+	return writePage(w, ".html", c, h.renderDocument)
+}
+
+// WritePages writes the reference of the command and each of its subcommands, as found by
+// [command.Command.Walk], into the given directory. Each file is a complete document named
+// by [PageName], with the extension ".html".
+func (h HTML) WritePages(dir string, c *command.Command) error {
+	return writePages(dir, ".html", c, h.renderDocument)
+}
+
+// WriteDocument writes the reference of the command and each of its subcommands
+// as a single document, linked by anchors.
+func (h HTML) WriteDocument(w io.Writer, c *command.Command) error {
+	pages, err := document(c, h.render)
+	if err != nil {
+		return err
+	}
+
+	return htmlTemplate.ExecuteTemplate(w, "document", struct {
+		Title string
+		Body  template.HTML
+	}{
+		h.title(c.Name()),
+		template.HTML(strings.Join(pages, "")),
+	})
+}
+
+func (h HTML) title(name string) string {
+	if h.Title == "" {
+		return name
+	}
+	return h.Title
+}
+
+func (h HTML) render(r reference) (string, error) {
+	var b strings.Builder
+	err := htmlTemplate.ExecuteTemplate(&b, "command", r)
+	return b.String(), err
+}
+
+func (h HTML) renderDocument(r reference) (string, error) {
+	body, err := h.render(r)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = htmlTemplate.ExecuteTemplate(&b, "document", struct {
+		Title string
+		Body  template.HTML
+	}{
+		h.title(r.Name),
+		template.HTML(body),
+	})
+	return b.String(), err
+}
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"lines": func(text string) []string { return strings.Split(text, "\n") },
+}).Parse(`
+{{- define "document" -}}
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+{{.Body -}}
+</body>
+</html>
+{{end}}
+
+{{- define "text"}}{{range $i, $line := lines .}}{{if $i}}<br>
+{{end}}{{$line}}{{end}}{{end}}
+
+{{- define "command" -}}
+<section id="{{.Anchor}}">
+<h1>{{.Name}}</h1>
+{{- if .Breadcrumbs}}
+<nav>{{range .Breadcrumbs}}<a href="{{.Href}}">{{.Text}}</a> › {{end}}{{index .Path (len .Breadcrumbs)}}</nav>
+{{- end}}
+<p>{{template "text" .Usage}}</p>
+<h2>Usage</h2>
+<pre><code>{{.Synopsis}}</code></pre>
+{{- if .Flags}}
+<h2>Options</h2>
+<table>
+<tr><th>Name</th><th>Type</th><th>Default</th><th>Required</th><th>Env</th><th>Description</th></tr>
+{{- range .Flags}}
+<tr><td><code>-{{.Name}}</code></td><td>{{.Type}}</td><td>{{with .Default}}<code>{{.}}</code>{{end}}</td><td>{{if .Required}}yes{{else}}no{{end}}</td><td>{{with .Env}}<code>{{.}}</code>{{end}}</td><td>{{template "text" .Summary}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Subcommands}}
+<h2>Commands</h2>
+<table>
+<tr><th>Command</th><th>Description</th></tr>
+{{- range .Subcommands}}
+{{- $href := index $.Links .Name}}
+<tr><td>{{if $href}}<a href="{{$href}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td>{{.Usage}}</td></tr>
+{{- end}}
+</table>
+{{- else if .Positional}}
+<h2>Arguments</h2>
+<table>
+<tr><th>Name</th><th>Required</th><th>Default</th><th>Description</th></tr>
+{{- range .Positional}}
+<tr><td><code>{{.Name}}</code></td><td>{{if .Required}}yes{{else}}no{{end}}</td><td>{{if not .Required}}<code>{{.Default}}</code>{{end}}</td><td>{{template "text" .Summary}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Examples}}
+<h2>Examples</h2>
+{{- range .Examples}}
+<pre><code>{{.Command}}</code></pre>
+{{- with .Usage}}
+<p>{{template "text" .}}</p>
+{{- end}}
+{{- end}}
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+<p>{{template "text" .Text}}</p>
+{{- end}}
+</section>
+{{end}}
+`))
@@ -0,0 +1,236 @@
+package doc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/command"
+	"github.com/michaeljpetter/command/value"
+	"io"
+	"strconv"
+)
+
+// SchemaVersion is the version of the JSON description schema written by [WriteJSON].
+// It changes only when the schema changes incompatibly; fields may be added
+// within a version, and readers should ignore fields they do not recognize.
+const SchemaVersion = 1
+
+// Spec is the machine-readable description of a command tree, as written by [WriteJSON].
+// It describes the command line accepted, for tools such as linters and command line builders,
+// and may be read back by [ReadJSON] to build a compatible command by [Build].
+type Spec struct {
+	Schema  int          `json:"schema"`
+	Command *CommandSpec `json:"command"`
+}
+
+// CommandSpec describes a single command and its subcommands.
+type CommandSpec struct {
+	Name        string           `json:"name"`
+	Path        []string         `json:"path"`
+	Usage       string           `json:"usage"`
+	Builtin     bool             `json:"builtin,omitempty"`
+	Hidden      bool             `json:"hidden,omitempty"`
+	Flags       []FlagSpec       `json:"flags,omitempty"`
+	Positional  []PositionalSpec `json:"positional,omitempty"`
+	Subcommands []*CommandSpec   `json:"subcommands,omitempty"`
+	Examples    []ExampleSpec    `json:"examples,omitempty"`
+	Sections    []SectionSpec    `json:"sections,omitempty"`
+}
+
+// FlagSpec describes a single flag.
+// Its default is given as the raw value accepted on the command line,
+// omitted when it is the zero value, and redacted when the flag is secret.
+type FlagSpec struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type,omitempty"`
+	Usage       string           `json:"usage"`
+	Default     string           `json:"default,omitempty"`
+	Required    bool             `json:"required,omitempty"`
+	Choices     []string         `json:"choices,omitempty"`
+	Constraints []ConstraintSpec `json:"constraints,omitempty"`
+	Secret      bool             `json:"secret,omitempty"`
+	Env         string           `json:"env,omitempty"`
+	Hidden      bool             `json:"hidden,omitempty"`
+}
+
+// PositionalSpec describes a single positional parameter.
+// Its default is given as for a [FlagSpec], and only when it is not required.
+type PositionalSpec struct {
+	Name        string           `json:"name"`
+	Type        string           `json:"type,omitempty"`
+	Usage       string           `json:"usage"`
+	Default     string           `json:"default,omitempty"`
+	Required    bool             `json:"required,omitempty"`
+	Choices     []string         `json:"choices,omitempty"`
+	Constraints []ConstraintSpec `json:"constraints,omitempty"`
+	Secret      bool             `json:"secret,omitempty"`
+}
+
+// ConstraintSpec describes a constraint applied by a check, as by [value.Constraint].
+type ConstraintSpec struct {
+	Description  string   `json:"description,omitempty"`
+	Min          string   `json:"min,omitempty"`
+	Max          string   `json:"max,omitempty"`
+	MinExclusive bool     `json:"minExclusive,omitempty"`
+	MaxExclusive bool     `json:"maxExclusive,omitempty"`
+	Choices      []string `json:"choices,omitempty"`
+}
+
+func newConstraintSpecs(constraints []value.Constraint) []ConstraintSpec {
+	var specs []ConstraintSpec
+	for _, c := range constraints {
+		specs = append(specs, ConstraintSpec(c))
+	}
+	return specs
+}
+
+// ExampleSpec describes an example invocation of a command.
+type ExampleSpec struct {
+	Command string `json:"command"`
+	Usage   string `json:"usage,omitempty"`
+}
+
+// SectionSpec describes an additional section of command help.
+type SectionSpec struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// Describe returns the [Spec] of the command and all of its subcommands, as found by [command.Command.Walk].
+// Built-in subcommands are included by name and usage only.
+func Describe(c *command.Command) (Spec, error) {
+	var root *CommandSpec
+	specs := make(map[string]*CommandSpec)
+
+	err := c.Walk(func(c *command.Command) error {
+		d := c.Describe()
+
+		spec, ok := specs[d.Name]
+		if !ok {
+			spec = new(CommandSpec)
+			root = spec
+		}
+		*spec = newCommandSpec(d)
+
+		for _, s := range d.Subcommands {
+			child := &CommandSpec{
+				Name:    s.Name,
+				Path:    append(d.Path[:len(d.Path):len(d.Path)], s.Name),
+				Usage:   s.Usage,
+				Builtin: s.Builtin,
+				Hidden:  s.Hidden,
+			}
+			spec.Subcommands = append(spec.Subcommands, child)
+			specs[d.Name+" "+s.Name] = child
+		}
+
+		return nil
+	})
+
+	return Spec{SchemaVersion, root}, err
+}
+
+func newCommandSpec(d command.Description) CommandSpec {
+	spec := CommandSpec{
+		Name:   d.Path[len(d.Path)-1],
+		Path:   d.Path,
+		Usage:  d.Usage,
+		Hidden: d.Hidden,
+	}
+
+	for _, f := range d.Flags {
+		spec.Flags = append(spec.Flags, FlagSpec{
+			Name:        f.Name,
+			Type:        f.ValueType,
+			Usage:       f.Usage,
+			Default:     rawDefault(f.ValueType, f.Default),
+			Required:    f.Required,
+			Choices:     f.Choices,
+			Constraints: newConstraintSpecs(f.Constraints),
+			Secret:      f.Secret,
+			Env:         f.Env,
+			Hidden:      f.Hidden,
+		})
+	}
+
+	for _, p := range d.Positional {
+		positional := PositionalSpec{
+			Name:        p.Name,
+			Type:        p.ValueType,
+			Usage:       p.Usage,
+			Required:    p.Required,
+			Choices:     p.Choices,
+			Constraints: newConstraintSpecs(p.Constraints),
+			Secret:      p.Secret,
+		}
+		if !p.Required {
+			positional.Default = rawDefault(p.ValueType, p.Default)
+		}
+		spec.Positional = append(spec.Positional, positional)
+	}
+
+	for _, e := range d.Examples {
+		spec.Examples = append(spec.Examples, ExampleSpec{e.Command, e.Usage})
+	}
+
+	for _, s := range d.Sections {
+		spec.Sections = append(spec.Sections, SectionSpec{s.Title, s.Text})
+	}
+
+	return spec
+}
+
+// rawDefault returns the default value as displayed, unquoting it for string values.
+func rawDefault(valueType, defValue string) string {
+	if valueType == "string" {
+		if raw, err := strconv.Unquote(defValue); err == nil {
+			return raw
+		}
+	}
+	return defValue
+}
+
+// Find returns the spec of the command with the given path below this one, or nil if there is none.
+func (c *CommandSpec) Find(path ...string) *CommandSpec {
+	if len(path) == 0 {
+		return c
+	}
+
+	for _, s := range c.Subcommands {
+		if s.Name == path[0] {
+			return s.Find(path[1:]...)
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes the [Spec] of the command and all of its subcommands as indented JSON.
+func WriteJSON(w io.Writer, c *command.Command) error {
+	spec, err := Describe(c)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(spec)
+}
+
+// ReadJSON reads a [Spec] as written by [WriteJSON],
+// failing if its schema version is not [SchemaVersion].
+func ReadJSON(r io.Reader) (Spec, error) {
+	var spec Spec
+	if err := json.NewDecoder(r).Decode(&spec); err != nil {
+		return Spec{}, err
+	}
+
+	if spec.Schema != SchemaVersion {
+		return Spec{}, fmt.Errorf("unsupported schema version %d, expected %d", spec.Schema, SchemaVersion)
+	}
+	if spec.Command == nil {
+		return Spec{}, errors.New("missing command")
+	}
+
+	return spec, nil
+}
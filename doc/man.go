@@ -0,0 +1,185 @@
+// Package doc generates reference documentation, such as man pages,
+// by introspecting a tree of commands. Hidden flags and subcommands are left out
+// of all documentation but the JSON description, which marks them as hidden.
+package doc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/command"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Man configures the generation of man(7) pages.
+type Man struct {
+	// Section is the manual section of the pages, defaulting to "1".
+	Section string
+
+	// Date, Source, and Manual fill the remaining fields of the page header,
+	// and may be left empty.
+	Date   string
+	Source string
+	Manual string
+}
+
+// PageName returns the name of the page describing a command,
+// which is its path joined by dashes, such as "prog-sub".
+func PageName(d command.Description) string {
+	return strings.Join(d.Path, "-")
+}
+
+func (m Man) section() string {
+	if m.Section == "" {
+		return "1"
+	}
+	return m.Section
+}
+
+// WritePage writes the man page for a single command.
+func (m Man) WritePage(w io.Writer, c *command.Command) error {
+	_, err := io.WriteString(w, m.page(c.Describe().Visible()))
+	return err
+}
+
+// WritePages writes a man page for the command and each of its subcommands, as found by
+// [command.Command.Walk], into the given directory. Each page is named by [PageName],
+// with the section as its extension.
+func (m Man) WritePages(dir string, c *command.Command) error {
+	return walk(c, func(d command.Description) error {
+		f, err := os.Create(filepath.Join(dir, PageName(d)+"."+m.section()))
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(f, m.page(d))
+		return errors.Join(err, f.Close())
+	})
+}
+
+func (m Man) page(d command.Description) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, ".TH %s %s %s %s %s\n",
+		quote(strings.ToUpper(PageName(d))), quote(m.section()), quote(m.Date), quote(m.Source), quote(m.Manual))
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s \\- %s\n", escape(PageName(d)), escape(strings.SplitN(d.Usage, "\n", 2)[0]))
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n", escape(d.Name))
+	if synopsis := strings.TrimPrefix(d.Synopsis(), d.Name+" "); synopsis != d.Synopsis() {
+		fmt.Fprintf(&b, "%s\n", escape(synopsis))
+	}
+
+	b.WriteString(".SH DESCRIPTION\n")
+	b.WriteString(paragraphs(d.Usage))
+
+	if d.Flags != nil {
+		b.WriteString(".SH OPTIONS\n")
+		for _, f := range d.Flags {
+			fmt.Fprintf(&b, ".TP\n\\fB\\-%s\\fR", escape(f.Name))
+			if f.Type != "" {
+				fmt.Fprintf(&b, " \\fI%s\\fR", escape(f.Type))
+			}
+			fmt.Fprintf(&b, "\n%s\n", escape(f.Description()))
+		}
+	}
+
+	if d.Subcommands != nil {
+		b.WriteString(".SH COMMANDS\n")
+		for _, s := range d.Subcommands {
+			fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", escape(s.Name), escape(s.Usage))
+		}
+	} else if d.Positional != nil {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, p := range d.Positional {
+			fmt.Fprintf(&b, ".TP\n\\fI%s\\fR\n%s\n", escape(p.Name), escape(p.Description()))
+		}
+	}
+
+	if d.Examples != nil {
+		b.WriteString(".SH EXAMPLES\n")
+		for _, e := range d.Examples {
+			if e.Usage == "" {
+				fmt.Fprintf(&b, ".PP\n\\fB%s\\fR\n", escape(e.Command))
+			} else {
+				fmt.Fprintf(&b, ".TP\n\\fB%s\\fR\n%s\n", escape(e.Command), escape(e.Usage))
+			}
+		}
+	}
+
+	var seeAlso string
+	for _, s := range d.Sections {
+		if strings.EqualFold(s.Title, "See Also") {
+			seeAlso = s.Text
+			continue
+		}
+		fmt.Fprintf(&b, ".SH %s\n", escape(strings.ToUpper(s.Title)))
+		b.WriteString(paragraphs(s.Text))
+	}
+
+	var related []string
+	if 1 < len(d.Path) {
+		related = append(related, strings.Join(d.Path[:len(d.Path)-1], "-"))
+	}
+	for _, s := range d.Subcommands {
+		if !s.Builtin {
+			related = append(related, PageName(d)+"-"+s.Name)
+		}
+	}
+
+	if related != nil || seeAlso != "" {
+		b.WriteString(".SH SEE ALSO\n")
+		for i, name := range related {
+			separator := ","
+			if i == len(related)-1 {
+				separator = ""
+			}
+			fmt.Fprintf(&b, ".BR %s (%s)%s\n", escape(name), m.section(), separator)
+		}
+		if seeAlso != "" {
+			if related != nil {
+				b.WriteString(".PP\n")
+			}
+			b.WriteString(paragraphs(seeAlso))
+		}
+	}
+
+	return b.String()
+}
+
+// paragraphs formats text as filled paragraphs, separated wherever the text has blank lines.
+func paragraphs(text string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			b.WriteString(".PP\n")
+		} else {
+			b.WriteString(escape(line) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// escape escapes text for roff, so that backslashes and dashes are displayed literally,
+// and no line is interpreted as a request.
+func escape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// quote quotes an argument to a roff request.
+func quote(text string) string {
+	return `"` + strings.ReplaceAll(escape(text), `"`, `\(dq`) + `"`
+}
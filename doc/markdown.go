@@ -0,0 +1,142 @@
+package doc
+
+import (
+	"fmt"
+	"github.com/michaeljpetter/command"
+	"io"
+	"strings"
+)
+
+// Markdown generates reference documentation in Markdown.
+type Markdown struct{}
+
+// WritePage writes the reference of a single command, linking to
+// any related commands as written by [Markdown.WritePages].
+func (m Markdown) WritePage(w io.Writer, c *command.Command) error {
+	return writePage(w, ".md", c, m.render)
+}
+
+// WritePages writes the reference of the command and each of its subcommands, as found by
+// [command.Command.Walk], into the given directory. Each file is named by [PageName],
+// with the extension ".md".
+func (m Markdown) WritePages(dir string, c *command.Command) error {
+	return writePages(dir, ".md", c, m.render)
+}
+
+// WriteDocument writes the reference of the command and each of its subcommands
+// as a single document, linked by anchors.
+func (m Markdown) WriteDocument(w io.Writer, c *command.Command) error {
+	pages, err := document(c, m.render)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, strings.Join(pages, "\n"))
+	return err
+}
+
+func (m Markdown) render(r reference) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<a id=\"%s\"></a>\n\n", r.Anchor)
+	fmt.Fprintf(&b, "# %s\n\n", markdownText(r.Name))
+
+	if r.Breadcrumbs != nil {
+		for _, crumb := range r.Breadcrumbs {
+			fmt.Fprintf(&b, "[%s](%s) › ", markdownText(crumb.Text), crumb.Href)
+		}
+		fmt.Fprintf(&b, "%s\n\n", markdownText(r.Path[len(r.Path)-1]))
+	}
+
+	fmt.Fprintf(&b, "%s\n\n", markdownText(r.Usage))
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n\n", r.Synopsis())
+
+	if r.Flags != nil {
+		b.WriteString("## Options\n\n")
+		b.WriteString("| Name | Type | Default | Required | Env | Description |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, f := range r.Flags {
+			fmt.Fprintf(&b, "| `-%s` | %s | %s | %s | %s | %s |\n",
+				f.Name, markdownCell(f.Type), markdownCode(f.Default), yesNo(f.Required), markdownCode(f.Env), markdownCell(f.Summary()))
+		}
+		b.WriteString("\n")
+	}
+
+	if r.Subcommands != nil {
+		b.WriteString("## Commands\n\n")
+		b.WriteString("| Command | Description |\n")
+		b.WriteString("| --- | --- |\n")
+		for _, s := range r.Subcommands {
+			name := markdownCell(s.Name)
+			if href, ok := r.Links[s.Name]; ok {
+				name = fmt.Sprintf("[%s](%s)", name, href)
+			}
+			fmt.Fprintf(&b, "| %s | %s |\n", name, markdownCell(s.Usage))
+		}
+		b.WriteString("\n")
+	} else if r.Positional != nil {
+		b.WriteString("## Arguments\n\n")
+		b.WriteString("| Name | Required | Default | Description |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, p := range r.Positional {
+			defValue := ""
+			if !p.Required {
+				defValue = markdownCode(p.Default)
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n",
+				p.Name, yesNo(p.Required), defValue, markdownCell(p.Summary()))
+		}
+		b.WriteString("\n")
+	}
+
+	if r.Examples != nil {
+		b.WriteString("## Examples\n\n")
+		for _, e := range r.Examples {
+			fmt.Fprintf(&b, "```\n%s\n```\n\n", e.Command)
+			if e.Usage != "" {
+				fmt.Fprintf(&b, "%s\n\n", markdownText(e.Usage))
+			}
+		}
+	}
+
+	for _, s := range r.Sections {
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n", markdownText(s.Title), markdownText(s.Text))
+	}
+
+	return b.String(), nil
+}
+
+// yesNo formats a boolean table cell.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;", "#", `\#`,
+)
+
+// markdownText escapes text for Markdown, keeping any blank lines as paragraph breaks
+// and other line breaks as hard breaks.
+func markdownText(text string) string {
+	paragraphs := strings.Split(markdownEscaper.Replace(text), "\n\n")
+	for i, paragraph := range paragraphs {
+		paragraphs[i] = strings.ReplaceAll(paragraph, "\n", "  \n")
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// markdownCell escapes text for a Markdown table cell.
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(markdownEscaper.Replace(text), "|", `\|`), "\n", "<br>")
+}
+
+// markdownCode formats text as inline code, or returns it empty.
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(text, "|", `\|`) + "`"
+}
@@ -0,0 +1,115 @@
+package doc
+
+import (
+	"errors"
+	"github.com/michaeljpetter/command"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// link is a hyperlink to the reference of a command.
+type link struct {
+	Text string
+	Href string
+}
+
+// reference is the reference documentation of a single command, with links resolved.
+type reference struct {
+	command.Description
+
+	// Anchor identifies the command within a combined document.
+	Anchor string
+
+	// Breadcrumbs links to each parent command, starting from the root.
+	Breadcrumbs []link
+
+	// Links holds the link to each subcommand by name, which is empty for built-in subcommands.
+	Links map[string]string
+}
+
+// render renders the reference of a single command.
+type render func(reference) (string, error)
+
+// newReference resolves the links of a command description, using href to find
+// the location of the command with the given page name.
+func newReference(d command.Description, href func(string) string) reference {
+	r := reference{
+		Description: d,
+		Anchor:      PageName(d),
+		Links:       make(map[string]string),
+	}
+
+	for i := range len(d.Path) - 1 {
+		r.Breadcrumbs = append(r.Breadcrumbs, link{d.Path[i], href(strings.Join(d.Path[:i+1], "-"))})
+	}
+
+	for _, s := range d.Subcommands {
+		if !s.Builtin {
+			r.Links[s.Name] = href(PageName(d) + "-" + s.Name)
+		}
+	}
+
+	return r
+}
+
+// walk calls visit with the visible description of the command and each of its subcommands,
+// as found by [command.Command.Walk], skipping any hidden command.
+func walk(c *command.Command, visit func(command.Description) error) error {
+	return c.Walk(func(c *command.Command) error {
+		if d := c.Describe(); !d.Hidden {
+			return visit(d.Visible())
+		}
+		return nil
+	})
+}
+
+// writePages writes the reference of the command and each of its subcommands
+// into the given directory, one file per command, named by [PageName] with the given extension.
+func writePages(dir, ext string, c *command.Command, render render) error {
+	href := func(name string) string { return name + ext }
+
+	return walk(c, func(d command.Description) error {
+		page, err := render(newReference(d, href))
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Create(filepath.Join(dir, PageName(d)+ext))
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(f, page)
+		return errors.Join(err, f.Close())
+	})
+}
+
+// writePage writes the reference of a single command, linking to others as written by writePages.
+func writePage(w io.Writer, ext string, c *command.Command, render render) error {
+	href := func(name string) string { return name + ext }
+
+	page, err := render(newReference(c.Describe().Visible(), href))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, page)
+	return err
+}
+
+// document renders the reference of the command and each of its subcommands,
+// in the order visited by [command.Command.Walk], linked by anchors.
+func document(c *command.Command, render render) ([]string, error) {
+	href := func(name string) string { return "#" + name }
+
+	var pages []string
+	err := walk(c, func(d command.Description) error {
+		page, err := render(newReference(d, href))
+		pages = append(pages, page)
+		return err
+	})
+
+	return pages, err
+}
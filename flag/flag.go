@@ -3,7 +3,14 @@
 package flag
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"github.com/michaeljpetter/command/internal"
+	"github.com/michaeljpetter/command/value"
+	"os"
+	"strconv"
+	"strings"
 )
 
 // Aliases for the [flag.ErrorHandling] values.
@@ -19,6 +26,9 @@ type ErrorHandling = flag.ErrorHandling
 // Value aliases the [flag.Value] type.
 type Value = flag.Value
 
+// Getter aliases the [flag.Getter] type.
+type Getter = flag.Getter
+
 // Flag aliases the [flag.Flag] type.
 type Flag = flag.Flag
 
@@ -27,11 +37,187 @@ type Flag = flag.Flag
 // with the addition of a final variadic parameter that can be used to add value checks to the flag.
 type FlagSet struct {
 	*flag.FlagSet
+
+	// Wrap enables wrapping of usage output to the column given by [FlagSet.WrapWidth],
+	// with continuation lines indented under the start of each description.
+	Wrap bool
+
+	// Width is the column to which usage output is wrapped when enabled.
+	// When zero, it defaults to the value of the COLUMNS environment variable, or 80.
+	Width int
+
+	// CollectErrors continues parsing past any flags which fail, reporting every failure at once.
+	CollectErrors bool
+
+	env    map[string]string
+	hidden map[string]bool
 }
 
 // NewFlagSet creates a new extended [FlagSet].
 func NewFlagSet(name string, errorHandling flag.ErrorHandling) *FlagSet {
 	return &FlagSet{
-		flag.NewFlagSet(name, errorHandling),
+		FlagSet: flag.NewFlagSet(name, errorHandling),
+	}
+}
+
+// WrapWidth returns the column to which usage output is wrapped,
+// or zero if wrapping is not enabled.
+func (f *FlagSet) WrapWidth() int {
+	if !f.Wrap {
+		return 0
+	}
+	if 0 < f.Width {
+		return f.Width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && 0 < columns {
+		return columns
+	}
+	return 80
+}
+
+// DocumentEnv records the environment variable from which the program reads the named flag,
+// so that it can be shown in usage and documentation. The variable is not read by parsing.
+// It panics if no such flag is defined.
+func (f *FlagSet) DocumentEnv(name, variable string) {
+	if f.Lookup(name) == nil {
+		panic(fmt.Sprintf("no such flag -%s", name))
+	}
+	if f.env == nil {
+		f.env = make(map[string]string)
+	}
+	f.env[name] = variable
+}
+
+// Hide hides the named flag from usage and documentation, though it may still be given.
+// It panics if no such flag is defined.
+func (f *FlagSet) Hide(name string) {
+	if f.Lookup(name) == nil {
+		panic(fmt.Sprintf("no such flag -%s", name))
+	}
+	if f.hidden == nil {
+		f.hidden = make(map[string]bool)
+	}
+	f.hidden[name] = true
+}
+
+// Hidden indicates whether the named flag is hidden by [FlagSet.Hide].
+func (f *FlagSet) Hidden(name string) bool {
+	return f.hidden[name]
+}
+
+// Env returns the environment variable documented for the named flag, or an empty string if there is none.
+func (f *FlagSet) Env(name string) string {
+	return f.env[name]
+}
+
+// UnquoteUsage aliases the [flag.UnquoteUsage] function.
+var UnquoteUsage = flag.UnquoteUsage
+
+// ErrHelp aliases the [flag.ErrHelp] error, which is returned when
+// the -help or -h flag is invoked but no such flag is defined.
+var ErrHelp = flag.ErrHelp
+
+// Secret is implemented by values which must never be displayed, such as passwords and tokens.
+// The String method of a secret value should return [value.Redacted] in place of any non-empty value,
+// and any error in which the value would be quoted displays [value.Redacted] instead.
+type Secret interface {
+	IsSecret() bool
+}
+
+// Display returns the raw argument given for a value, or [value.Redacted] if the value is a secret.
+func Display(v any, raw string) string {
+	if secret, ok := v.(Secret); ok && secret.IsSecret() {
+		return value.Redacted
+	}
+	return raw
+}
+
+// Chooser is implemented by values which accept only a fixed set of choices,
+// such as enumerated values, so that the choices can be listed in usage output.
+type Chooser interface {
+	Choices() []string
+}
+
+// Constrained is implemented by values which can describe the constraints applied by their checks,
+// so that the constraints can be listed in usage output.
+type Constrained interface {
+	Constraints() []value.Constraint
+}
+
+// PrintDefaults behaves as [flag.FlagSet.PrintDefaults],
+// additionally listing the choices of any flag whose value implements [Chooser],
+// the constraints of any flag whose value implements [Constrained],
+// and the environment variable documented for any flag, after its usage,
+// omitting any flag hidden by [FlagSet.Hide], and wrapping usage when [FlagSet.Wrap] is enabled.
+func (f *FlagSet) PrintDefaults() {
+	visible := flag.NewFlagSet(f.Name(), ContinueOnError)
+	visible.SetOutput(f.Output())
+
+	f.VisitAll(func(flag *Flag) {
+		if f.Hidden(flag.Name) {
+			return
+		}
+
+		usage := flag.Usage + Describe(flag.Value) + DescribeEnv(f.Env(flag.Name))
+
+		// The usage column begins at the first tab stop.
+		if width := f.WrapWidth(); 0 < width {
+			usage = strings.Join(internal.Wrap(usage, width, 8), "\n")
+		}
+
+		visible.Var(flag.Value, flag.Name, usage)
+		visible.Lookup(flag.Name).DefValue = flag.DefValue
+	})
+
+	visible.PrintDefaults()
+}
+
+// Describe returns the choices of a value implementing [Chooser],
+// followed by the constraints of a value implementing [Constrained], as appended to usage.
+func Describe(v Value) string {
+	var b strings.Builder
+
+	if chooser, ok := v.(Chooser); ok {
+		fmt.Fprintf(&b, " (one of: %s)", strings.Join(chooser.Choices(), ", "))
+	}
+
+	if constrained, ok := v.(Constrained); ok {
+		if description := value.DescribeConstraints(constrained.Constraints()); description != "" {
+			fmt.Fprintf(&b, " (%s)", description)
+		}
+	}
+
+	return b.String()
+}
+
+// DescribeEnv returns the environment variable documented for a flag as appended to usage,
+// or an empty string if there is none.
+func DescribeEnv(variable string) string {
+	if variable == "" {
+		return ""
+	}
+	return " (env: " + variable + ")"
+}
+
+// DefaultText returns the default value of a flag as displayed by [flag.FlagSet.PrintDefaults],
+// or an empty string if the default is the zero value of its type.
+func DefaultText(f *Flag) (string, error) {
+	var b strings.Builder
+	single := flag.NewFlagSet("", flag.ContinueOnError)
+	single.SetOutput(&b)
+	single.Var(f.Value, f.Name, "")
+	single.Lookup(f.Name).DefValue = f.DefValue
+	single.PrintDefaults()
+
+	// Any problem determining the default is printed after a blank line.
+	line, problem, _ := strings.Cut(b.String(), "\n\n")
+	if problem != "" {
+		return "", errors.New(strings.TrimSpace(problem))
+	}
+
+	_, defValue, ok := strings.Cut(line, "(default ")
+	if !ok {
+		return "", nil
 	}
+	return strings.TrimSuffix(defValue, ")\n"), nil
 }
@@ -0,0 +1,176 @@
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// boolFlag mirrors the interface used by the stdlib to identify flags which need no argument.
+type boolFlag interface {
+	IsBoolFlag() bool
+}
+
+// FlagError reports a failure to parse a single flag.
+type FlagError struct {
+	// Name is the name of the flag, or the malformed argument if its syntax is invalid.
+	Name string
+
+	// Err is the underlying error, such as returned by the Set method of the flag value, if any.
+	Err error
+
+	message string
+}
+
+func (e *FlagError) Error() string {
+	return e.message
+}
+
+func (e *FlagError) Unwrap() error {
+	return e.Err
+}
+
+// Parse behaves as [flag.FlagSet.Parse], except that the values of flags
+// implementing [Secret] are never displayed in errors.
+//
+// When CollectErrors is set, parsing continues past any flags which fail,
+// and all failures are printed before usage is printed once, and returned joined by [errors.Join].
+func (f *FlagSet) Parse(arguments []string) error {
+	var err error
+	if f.CollectErrors {
+		err = f.ParseAll(arguments)
+	} else {
+		_, err = f.parseOnce(arguments)
+	}
+
+	if err != nil && err != ErrHelp {
+		fmt.Fprintln(f.Output(), err)
+		f.usage()
+	}
+
+	if err != nil {
+		switch f.ErrorHandling() {
+		case ContinueOnError:
+			return err
+		case ExitOnError:
+			if err == ErrHelp {
+				os.Exit(0)
+			}
+			os.Exit(2)
+		case PanicOnError:
+			panic(err)
+		}
+	}
+
+	return nil
+}
+
+// ParseAll parses flags in the same way as [FlagSet.Parse], but continues past any flags which fail.
+// It returns a single failure as a [*FlagError], or multiple failures joined by [errors.Join],
+// without printing them, and regardless of the error handling of the flag set.
+// A request for help stops parsing, printing usage and returning [ErrHelp].
+func (f *FlagSet) ParseAll(arguments []string) error {
+	var errs []error
+	for {
+		skip, err := f.parseOnce(arguments)
+		if err == ErrHelp {
+			return err
+		}
+		if err == nil {
+			break
+		}
+		errs = append(errs, err)
+		arguments = f.Args()[skip:]
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// parseOnce delegates to [flag.FlagSet.Parse], stopping at the first failure, which is returned
+// as a [*FlagError] without being printed, after the number of remaining arguments to skip
+// in order to continue past it. A request for help prints usage and returns [ErrHelp].
+func (f *FlagSet) parseOnce(arguments []string) (int, error) {
+	errorHandling, output, usage := f.ErrorHandling(), f.Output(), f.FlagSet.Usage
+
+	// The stdlib reports nothing itself, and leaves the error handling to the caller.
+	f.Init(f.Name(), ContinueOnError)
+	f.SetOutput(io.Discard)
+	f.FlagSet.Usage = func() {}
+
+	var failed *FlagError
+	f.VisitAll(func(flag *Flag) {
+		flag.Value = redacting{flag.Value, flag.Name, &failed}
+	})
+
+	err := f.FlagSet.Parse(arguments)
+
+	f.VisitAll(func(flag *Flag) {
+		flag.Value = flag.Value.(redacting).Value
+	})
+	f.Init(f.Name(), errorHandling)
+	f.SetOutput(output)
+	f.FlagSet.Usage = usage
+
+	switch {
+	case err == nil:
+		return 0, nil
+	case err == ErrHelp:
+		f.usage()
+		return 0, err
+	case failed != nil:
+		return 0, failed
+	}
+
+	// The remaining errors name the flag, but never display its value.
+	message := err.Error()
+	if argument, ok := strings.CutPrefix(message, "bad flag syntax: "); ok {
+		// The malformed argument is not consumed.
+		return 1, &FlagError{Name: argument, message: message}
+	}
+	_, name, _ := strings.Cut(message, ": -")
+	return 0, &FlagError{Name: name, message: message}
+}
+
+// redacting wraps the value of a flag while parsing, recording any failure to set it
+// as a [*FlagError] in which the raw argument is displayed only if the value is not a [Secret].
+type redacting struct {
+	Value
+	name   string
+	failed **FlagError
+}
+
+func (r redacting) IsBoolFlag() bool {
+	b, ok := r.Value.(boolFlag)
+	return ok && b.IsBoolFlag()
+}
+
+func (r redacting) Set(raw string) error {
+	err := r.Value.Set(raw)
+	if err != nil {
+		format := "invalid value %q for flag -%s: %v"
+		if r.IsBoolFlag() {
+			format = "invalid boolean value %q for -%s: %v"
+		}
+		*r.failed = &FlagError{r.name, err, fmt.Sprintf(format, Display(r.Value, raw), r.name, err)}
+	}
+	return err
+}
+
+func (f *FlagSet) usage() {
+	if f.Usage != nil {
+		f.Usage()
+		return
+	}
+
+	if f.Name() == "" {
+		fmt.Fprintf(f.Output(), "Usage:\n")
+	} else {
+		fmt.Fprintf(f.Output(), "Usage of %s:\n", f.Name())
+	}
+	f.PrintDefaults()
+}
@@ -3,6 +3,8 @@ package flag
 import (
 	"github.com/michaeljpetter/command/internal"
 	"github.com/michaeljpetter/command/value"
+	"net/netip"
+	"net/url"
 	"time"
 )
 
@@ -20,6 +22,48 @@ func (f *FlagSet) Int(name string, value int, usage string, checks ...value.Chec
 	return p
 }
 
+// Int8Var behaves as [flag.FlagSet.IntVar] for an int8 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Int8Var(p *int8, name string, value int8, usage string, checks ...value.CheckFunc[int8]) {
+	f.Var(internal.NewInt8Value(&value, p, checks...), name, usage)
+}
+
+// Int8 behaves as [flag.FlagSet.Int] for an int8 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Int8(name string, value int8, usage string, checks ...value.CheckFunc[int8]) *int8 {
+	p := new(int8)
+	f.Int8Var(p, name, value, usage, checks...)
+	return p
+}
+
+// Int16Var behaves as [flag.FlagSet.IntVar] for an int16 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Int16Var(p *int16, name string, value int16, usage string, checks ...value.CheckFunc[int16]) {
+	f.Var(internal.NewInt16Value(&value, p, checks...), name, usage)
+}
+
+// Int16 behaves as [flag.FlagSet.Int] for an int16 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Int16(name string, value int16, usage string, checks ...value.CheckFunc[int16]) *int16 {
+	p := new(int16)
+	f.Int16Var(p, name, value, usage, checks...)
+	return p
+}
+
+// Int32Var behaves as [flag.FlagSet.IntVar] for an int32 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Int32Var(p *int32, name string, value int32, usage string, checks ...value.CheckFunc[int32]) {
+	f.Var(internal.NewInt32Value(&value, p, checks...), name, usage)
+}
+
+// Int32 behaves as [flag.FlagSet.Int] for an int32 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Int32(name string, value int32, usage string, checks ...value.CheckFunc[int32]) *int32 {
+	p := new(int32)
+	f.Int32Var(p, name, value, usage, checks...)
+	return p
+}
+
 // Int64Var behaves as [flag.FlagSet.Int64Var],
 // with an additional variadic parameter allowing checks to be applied to the parsed value.
 func (f *FlagSet) Int64Var(p *int64, name string, value int64, usage string, checks ...value.CheckFunc[int64]) {
@@ -48,6 +92,48 @@ func (f *FlagSet) Uint(name string, value uint, usage string, checks ...value.Ch
 	return p
 }
 
+// Uint8Var behaves as [flag.FlagSet.UintVar] for a uint8 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Uint8Var(p *uint8, name string, value uint8, usage string, checks ...value.CheckFunc[uint8]) {
+	f.Var(internal.NewUint8Value(&value, p, checks...), name, usage)
+}
+
+// Uint8 behaves as [flag.FlagSet.Uint] for a uint8 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Uint8(name string, value uint8, usage string, checks ...value.CheckFunc[uint8]) *uint8 {
+	p := new(uint8)
+	f.Uint8Var(p, name, value, usage, checks...)
+	return p
+}
+
+// Uint16Var behaves as [flag.FlagSet.UintVar] for a uint16 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Uint16Var(p *uint16, name string, value uint16, usage string, checks ...value.CheckFunc[uint16]) {
+	f.Var(internal.NewUint16Value(&value, p, checks...), name, usage)
+}
+
+// Uint16 behaves as [flag.FlagSet.Uint] for a uint16 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Uint16(name string, value uint16, usage string, checks ...value.CheckFunc[uint16]) *uint16 {
+	p := new(uint16)
+	f.Uint16Var(p, name, value, usage, checks...)
+	return p
+}
+
+// Uint32Var behaves as [flag.FlagSet.UintVar] for a uint32 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Uint32Var(p *uint32, name string, value uint32, usage string, checks ...value.CheckFunc[uint32]) {
+	f.Var(internal.NewUint32Value(&value, p, checks...), name, usage)
+}
+
+// Uint32 behaves as [flag.FlagSet.Uint] for a uint32 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Uint32(name string, value uint32, usage string, checks ...value.CheckFunc[uint32]) *uint32 {
+	p := new(uint32)
+	f.Uint32Var(p, name, value, usage, checks...)
+	return p
+}
+
 // Uint64Var behaves as [flag.FlagSet.Uint64Var],
 // with an additional variadic parameter allowing checks to be applied to the parsed value.
 func (f *FlagSet) Uint64Var(p *uint64, name string, value uint64, usage string, checks ...value.CheckFunc[uint64]) {
@@ -62,6 +148,20 @@ func (f *FlagSet) Uint64(name string, value uint64, usage string, checks ...valu
 	return p
 }
 
+// Float32Var behaves as [flag.FlagSet.Float64Var] for a float32 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Float32Var(p *float32, name string, value float32, usage string, checks ...value.CheckFunc[float32]) {
+	f.Var(internal.NewFloat32Value(&value, p, checks...), name, usage)
+}
+
+// Float32 behaves as [flag.FlagSet.Float64] for a float32 flag,
+// with an additional variadic parameter allowing checks to be applied to the parsed value.
+func (f *FlagSet) Float32(name string, value float32, usage string, checks ...value.CheckFunc[float32]) *float32 {
+	p := new(float32)
+	f.Float32Var(p, name, value, usage, checks...)
+	return p
+}
+
 // Float64Var behaves as [flag.FlagSet.Float64Var],
 // with an additional variadic parameter allowing checks to be applied to the parsed value.
 func (f *FlagSet) Float64Var(p *float64, name string, value float64, usage string, checks ...value.CheckFunc[float64]) {
@@ -103,3 +203,332 @@ func (f *FlagSet) Duration(name string, value time.Duration, usage string, check
 	f.DurationVar(p, name, value, usage, checks...)
 	return p
 }
+
+// ExtendedDurationVar defines an extended [time.Duration] flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// In addition to the syntax of [time.ParseDuration], extended durations accept days (d) and weeks (w) as units,
+// such as 7d or 1d12h, as well as ISO 8601 durations without years or months, such as P1DT2H.
+// They are displayed using days and weeks where they apply.
+func (f *FlagSet) ExtendedDurationVar(p *time.Duration, name string, value time.Duration, usage string, checks ...value.CheckFunc[time.Duration]) {
+	f.Var(internal.NewExtendedDurationValue(&value, p, checks...), name, usage)
+}
+
+// ExtendedDuration defines an extended [time.Duration] flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// In addition to the syntax of [time.ParseDuration], extended durations accept days (d) and weeks (w) as units,
+// such as 7d or 1d12h, as well as ISO 8601 durations without years or months, such as P1DT2H.
+// They are displayed using days and weeks where they apply.
+func (f *FlagSet) ExtendedDuration(name string, value time.Duration, usage string, checks ...value.CheckFunc[time.Duration]) *time.Duration {
+	p := new(time.Duration)
+	f.ExtendedDurationVar(p, name, value, usage, checks...)
+	return p
+}
+
+// ByteSizeVar defines a byte size flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Byte sizes accept an optional SI (KB, MB, ...) or IEC (KiB, MiB, ...) unit, with the trailing B optional,
+// and are displayed in whichever unit is most concise.
+func (f *FlagSet) ByteSizeVar(p *uint64, name string, value uint64, usage string, checks ...value.CheckFunc[uint64]) {
+	f.Var(internal.NewByteSizeValue(&value, p, checks...), name, usage)
+}
+
+// ByteSize defines a byte size flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Byte sizes accept an optional SI (KB, MB, ...) or IEC (KiB, MiB, ...) unit, with the trailing B optional,
+// and are displayed in whichever unit is most concise.
+func (f *FlagSet) ByteSize(name string, value uint64, usage string, checks ...value.CheckFunc[uint64]) *uint64 {
+	p := new(uint64)
+	f.ByteSizeVar(p, name, value, usage, checks...)
+	return p
+}
+
+// QuantityVar defines a quantity flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Quantities accept an optional SI prefix (k, M, G, ..., m, u, n),
+// and are displayed with the largest prefix that applies.
+func (f *FlagSet) QuantityVar(p *float64, name string, value float64, usage string, checks ...value.CheckFunc[float64]) {
+	f.Var(internal.NewQuantityValue(&value, p, checks...), name, usage)
+}
+
+// Quantity defines a quantity flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Quantities accept an optional SI prefix (k, M, G, ..., m, u, n),
+// and are displayed with the largest prefix that applies.
+func (f *FlagSet) Quantity(name string, value float64, usage string, checks ...value.CheckFunc[float64]) *float64 {
+	p := new(float64)
+	f.QuantityVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PercentVar defines a percentage flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Percentages accept a number suffixed with %, which is scaled to a fraction,
+// such that 75% is 0.75, and are displayed as a percentage. A bare number is rejected as ambiguous.
+func (f *FlagSet) PercentVar(p *float64, name string, value float64, usage string, checks ...value.CheckFunc[float64]) {
+	f.Var(internal.NewPercentValue(&value, p, checks...), name, usage)
+}
+
+// Percent defines a percentage flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Percentages accept a number suffixed with %, which is scaled to a fraction,
+// such that 75% is 0.75, and are displayed as a percentage. A bare number is rejected as ambiguous.
+func (f *FlagSet) Percent(name string, value float64, usage string, checks ...value.CheckFunc[float64]) *float64 {
+	p := new(float64)
+	f.PercentVar(p, name, value, usage, checks...)
+	return p
+}
+
+// TimeVar defines a [time.Time] flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Times are parsed by a default [value.TimeParser], accepting RFC 3339 and relative expressions such as now-2h.
+// Use [FlagSet.TimeLayoutsVar] for other layouts, or [FuncVar] with a configured [value.TimeParser].
+func (f *FlagSet) TimeVar(p *time.Time, name string, value time.Time, usage string, checks ...value.CheckFunc[time.Time]) {
+	f.Var(internal.NewTimeValue(&value, p, checks...), name, usage)
+}
+
+// Time defines a [time.Time] flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Times are parsed by a default [value.TimeParser], accepting RFC 3339 and relative expressions such as now-2h.
+// Use [FlagSet.TimeLayoutsVar] for other layouts, or [FuncVar] with a configured [value.TimeParser].
+func (f *FlagSet) Time(name string, value time.Time, usage string, checks ...value.CheckFunc[time.Time]) *time.Time {
+	p := new(time.Time)
+	f.TimeVar(p, name, value, usage, checks...)
+	return p
+}
+
+// TimeLayoutsVar defines a [time.Time] flag with the given name, default value, layouts, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Times are parsed by a [value.TimeParser] with the given layouts, which are tried in order,
+// the first also being used to display the default, and relative expressions such as now-2h.
+func (f *FlagSet) TimeLayoutsVar(p *time.Time, name string, value time.Time, layouts []string, usage string, checks ...value.CheckFunc[time.Time]) {
+	f.Var(internal.NewTimeLayoutsValue(&value, p, layouts, checks...), name, usage)
+}
+
+// TimeLayouts defines a [time.Time] flag with the given name, default value, layouts, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Times are parsed by a [value.TimeParser] with the given layouts, which are tried in order,
+// the first also being used to display the default, and relative expressions such as now-2h.
+func (f *FlagSet) TimeLayouts(name string, value time.Time, layouts []string, usage string, checks ...value.CheckFunc[time.Time]) *time.Time {
+	p := new(time.Time)
+	f.TimeLayoutsVar(p, name, value, layouts, usage, checks...)
+	return p
+}
+
+// DateVar defines a date flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Dates are parsed by a default [value.DateParser], accepting [time.DateOnly] and relative expressions such as yesterday.
+func (f *FlagSet) DateVar(p *time.Time, name string, value time.Time, usage string, checks ...value.CheckFunc[time.Time]) {
+	f.Var(internal.NewDateValue(&value, p, checks...), name, usage)
+}
+
+// Date defines a date flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Dates are parsed by a default [value.DateParser], accepting [time.DateOnly] and relative expressions such as yesterday.
+func (f *FlagSet) Date(name string, value time.Time, usage string, checks ...value.CheckFunc[time.Time]) *time.Time {
+	p := new(time.Time)
+	f.DateVar(p, name, value, usage, checks...)
+	return p
+}
+
+// LocationVar defines a [time.Location] flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Locations are loaded by name, as by [time.LoadLocation].
+func (f *FlagSet) LocationVar(p **time.Location, name string, value *time.Location, usage string, checks ...value.CheckFunc[*time.Location]) {
+	f.Var(internal.NewLocationValue(&value, p, checks...), name, usage)
+}
+
+// Location defines a [time.Location] flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Locations are loaded by name, as by [time.LoadLocation].
+func (f *FlagSet) Location(name string, value *time.Location, usage string, checks ...value.CheckFunc[*time.Location]) **time.Location {
+	p := new(*time.Location)
+	f.LocationVar(p, name, value, usage, checks...)
+	return p
+}
+
+// SecretVar defines a secret string flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Secrets are never displayed, either in usage output or in errors.
+// A value prefixed with @ names a file from which the secret is read, and - reads it from stdin,
+// or from the input of the shell when parsed within one. A literal leading @ is given as @@.
+func (f *FlagSet) SecretVar(p *string, name string, value string, usage string, checks ...value.CheckFunc[string]) {
+	f.Var(internal.NewSecretValue(&value, p, checks...), name, usage)
+}
+
+// Secret defines a secret string flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Secrets are never displayed, either in usage output or in errors.
+// A value prefixed with @ names a file from which the secret is read, and - reads it from stdin,
+// or from the input of the shell when parsed within one. A literal leading @ is given as @@.
+func (f *FlagSet) Secret(name string, value string, usage string, checks ...value.CheckFunc[string]) *string {
+	p := new(string)
+	f.SecretVar(p, name, value, usage, checks...)
+	return p
+}
+
+// URLVar defines a [url.URL] flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// URLs are parsed as by [url.Parse], and may be restricted to given schemes using [github.com/michaeljpetter/command/check.Scheme].
+func (f *FlagSet) URLVar(p **url.URL, name string, value *url.URL, usage string, checks ...value.CheckFunc[*url.URL]) {
+	f.Var(internal.NewURLValue(&value, p, checks...), name, usage)
+}
+
+// URL defines a [url.URL] flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// URLs are parsed as by [url.Parse], and may be restricted to given schemes using [github.com/michaeljpetter/command/check.Scheme].
+func (f *FlagSet) URL(name string, value *url.URL, usage string, checks ...value.CheckFunc[*url.URL]) **url.URL {
+	p := new(*url.URL)
+	f.URLVar(p, name, value, usage, checks...)
+	return p
+}
+
+// HostPortVar defines a host:port flag with the given name, default value, default port, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If the port is omitted, it is filled from the given default port, which, if empty, requires a port to be given.
+// Hosts are not resolved.
+func (f *FlagSet) HostPortVar(p *string, name string, value string, port string, usage string, checks ...value.CheckFunc[string]) {
+	f.Var(internal.NewHostPortValue(&value, p, port, checks...), name, usage)
+}
+
+// HostPort defines a host:port flag with the given name, default value, default port, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If the port is omitted, it is filled from the given default port, which, if empty, requires a port to be given.
+// Hosts are not resolved.
+func (f *FlagSet) HostPort(name string, value string, port string, usage string, checks ...value.CheckFunc[string]) *string {
+	p := new(string)
+	f.HostPortVar(p, name, value, port, usage, checks...)
+	return p
+}
+
+// AddrVar defines a [netip.Addr] flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Addresses are parsed as by [netip.ParseAddr].
+func (f *FlagSet) AddrVar(p *netip.Addr, name string, value netip.Addr, usage string, checks ...value.CheckFunc[netip.Addr]) {
+	f.Var(internal.NewTextValue(&value, p, checks...), name, usage)
+}
+
+// Addr defines a [netip.Addr] flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Addresses are parsed as by [netip.ParseAddr].
+func (f *FlagSet) Addr(name string, value netip.Addr, usage string, checks ...value.CheckFunc[netip.Addr]) *netip.Addr {
+	p := new(netip.Addr)
+	f.AddrVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PrefixVar defines a [netip.Prefix] flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Prefixes are parsed as by [netip.ParsePrefix].
+func (f *FlagSet) PrefixVar(p *netip.Prefix, name string, value netip.Prefix, usage string, checks ...value.CheckFunc[netip.Prefix]) {
+	f.Var(internal.NewTextValue(&value, p, checks...), name, usage)
+}
+
+// Prefix defines a [netip.Prefix] flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Prefixes are parsed as by [netip.ParsePrefix].
+func (f *FlagSet) Prefix(name string, value netip.Prefix, usage string, checks ...value.CheckFunc[netip.Prefix]) *netip.Prefix {
+	p := new(netip.Prefix)
+	f.PrefixVar(p, name, value, usage, checks...)
+	return p
+}
+
+// AddrPortVar defines a [netip.AddrPort] flag with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Address and port pairs are parsed as by [netip.ParseAddrPort].
+func (f *FlagSet) AddrPortVar(p *netip.AddrPort, name string, value netip.AddrPort, usage string, checks ...value.CheckFunc[netip.AddrPort]) {
+	f.Var(internal.NewTextValue(&value, p, checks...), name, usage)
+}
+
+// AddrPort defines a [netip.AddrPort] flag with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Address and port pairs are parsed as by [netip.ParseAddrPort].
+func (f *FlagSet) AddrPort(name string, value netip.AddrPort, usage string, checks ...value.CheckFunc[netip.AddrPort]) *netip.AddrPort {
+	p := new(netip.AddrPort)
+	f.AddrPortVar(p, name, value, usage, checks...)
+	return p
+}
+
+// TextVar defines a flag on f with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// It behaves as [flag.FlagSet.TextVar], but retains the type T of the value,
+// which is parsed and formatted via its [encoding.TextUnmarshaler] and [encoding.TextMarshaler] implementations.
+func TextVar[T any, P value.TextPointer[T]](f *FlagSet, p *T, name string, value T, usage string, checks ...value.CheckFunc[T]) {
+	f.Var(internal.NewTextValue[T, P](&value, p, checks...), name, usage)
+}
+
+// Text defines a flag on f with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// The value is parsed and formatted via its [encoding.TextUnmarshaler] and [encoding.TextMarshaler] implementations.
+func Text[T any, P value.TextPointer[T]](f *FlagSet, name string, value T, usage string, checks ...value.CheckFunc[T]) *T {
+	p := new(T)
+	TextVar[T, P](f, p, name, value, usage, checks...)
+	return p
+}
+
+// FuncVar defines a flag on f with the given name, default value, usage, parse and format functions, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If format is nil, the value is formatted as by [fmt.Sprint].
+func FuncVar[T any](f *FlagSet, p *T, name string, value T, usage string, parse value.ParseFunc[T], format value.FormatFunc[T], checks ...value.CheckFunc[T]) {
+	f.Var(internal.NewFuncValue(&value, p, parse, format, checks...), name, usage)
+}
+
+// Func defines a flag on f with the given name, default value, usage, parse and format functions, and checks.
+// The returned pointer receives the parsed value.
+//
+// If format is nil, the value is formatted as by [fmt.Sprint].
+func Func[T any](f *FlagSet, name string, value T, usage string, parse value.ParseFunc[T], format value.FormatFunc[T], checks ...value.CheckFunc[T]) *T {
+	p := new(T)
+	FuncVar(f, p, name, value, usage, parse, format, checks...)
+	return p
+}
+
+// EnumVar defines a flag on f with the given name, default value, usage, enumerated choices, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// The flag accepts the names and aliases of the choices, which are listed in usage output,
+// and displays its default by name.
+func EnumVar[T comparable](f *FlagSet, p *T, name string, value T, usage string, enum value.Enum[T], checks ...value.CheckFunc[T]) {
+	f.Var(internal.NewEnumValue(&value, p, enum, checks...), name, usage)
+}
+
+// Enum defines a flag on f with the given name, default value, usage, enumerated choices, and checks.
+// The returned pointer receives the parsed value.
+//
+// The flag accepts the names and aliases of the choices, which are listed in usage output,
+// and displays its default by name.
+func Enum[T comparable](f *FlagSet, name string, value T, usage string, enum value.Enum[T], checks ...value.CheckFunc[T]) *T {
+	p := new(T)
+	EnumVar(f, p, name, value, usage, enum, checks...)
+	return p
+}
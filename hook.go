@@ -0,0 +1,92 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+)
+
+// HookFunc defines a function that is run before or after a subcommand, given the
+// subcommand being run. A hook may abort execution of the subcommand by returning an error.
+type HookFunc func(*Command) error
+
+// hooks holds the before and after hooks to run around a subcommand, each ordered parent-to-child.
+type hooks struct {
+	before []HookFunc
+	after  []HookFunc
+}
+
+func (h hooks) join(other hooks) hooks {
+	return hooks{
+		slices.Concat(h.before, other.before),
+		slices.Concat(h.after, other.after),
+	}
+}
+
+// Before adds hooks to run before each direct subcommand of this command.
+//
+// Before hooks run before the handler of the subcommand is called, whether or not it then parses its arguments,
+// so the flags declared by its definition, as given to [Command.Define], hold their defaults. Should any return
+// an error, it is reported as by [Command.Parse], though without usage, the handler is not called,
+// and no further hooks are run.
+func (c *Command) Before(hooks ...HookFunc) {
+	c.hooks.before = append(c.hooks.before, hooks...)
+}
+
+// After adds hooks to run after each direct subcommand of this command, once its handler has returned,
+// whether or not it parsed its arguments successfully.
+// After hooks run in the reverse order to before hooks, and only if all before hooks succeeded.
+// All after hooks are run, and any errors are reported once all have run.
+func (c *Command) After(hooks ...HookFunc) {
+	c.hooks.after = append(c.hooks.after, hooks...)
+}
+
+// BeforeAll adds hooks to run before every subcommand below this command, at any depth, as by [Command.Before].
+// They run only around the subcommand which is ultimately executed, that is, one which defines no subcommands
+// of its own, so that they run once for each execution. As the hooks run before the handler, only subcommands
+// defined statically, as by [Command.Define], are known before then. Hooks added by ancestors run before
+// those added by descendants.
+func (c *Command) BeforeAll(hooks ...HookFunc) {
+	c.allHooks.before = append(c.allHooks.before, hooks...)
+}
+
+// AfterAll adds hooks to run after every subcommand below this command, at any depth,
+// as by [Command.After]. Hooks added by descendants run before those added by ancestors.
+func (c *Command) AfterAll(hooks ...HookFunc) {
+	c.allHooks.after = append(c.allHooks.after, hooks...)
+}
+
+// runBefore runs the before hooks of the command, before its handler is called, stopping at the first failure.
+// Inherited hooks are run only when the command has no subcommands.
+func (c *Command) runBefore() error {
+	c.pending = c.around
+	if !c.HasSubcommands() {
+		c.pending = c.inherited.join(c.around)
+	}
+
+	for _, hook := range c.pending.before {
+		if err := hook(c); err != nil {
+			fmt.Fprintln(c.Output(), err)
+			return reported{err}
+		}
+	}
+	c.ran = true
+	return nil
+}
+
+// runAfter runs the after hooks of the command in reverse, if its before hooks succeeded.
+func (c *Command) runAfter() error {
+	if !c.ran {
+		return nil
+	}
+
+	var errs []error
+	for _, hook := range slices.Backward(c.pending.after) {
+		errs = append(errs, hook(c))
+	}
+
+	if err := joinErrors(errs...); err != nil {
+		fmt.Fprintln(c.Output(), err)
+		return reported{err}
+	}
+	return nil
+}
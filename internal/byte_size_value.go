@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+type byteUnit struct {
+	symbol string
+	size   uint64
+}
+
+// byteUnits lists the accepted byte size units from largest to smallest.
+// Units with an "i" are IEC (powers of 1024), while the rest are SI (powers of 1000).
+var byteUnits = []byteUnit{
+	{"EiB", 1 << 60}, {"EB", 1e18},
+	{"PiB", 1 << 50}, {"PB", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6},
+	{"KiB", 1 << 10}, {"KB", 1e3},
+	{"B", 1},
+}
+
+func splitNumber(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	i := strings.IndexFunc(raw, func(r rune) bool {
+		return !strings.ContainsRune("0123456789.+-_", r)
+	})
+	if i < 0 {
+		return raw, ""
+	}
+	return raw[:i], strings.TrimSpace(raw[i:])
+}
+
+func parseByteSize(raw string) (uint64, error) {
+	number, unit := splitNumber(raw)
+
+	size, ok := uint64(1), unit == ""
+	for _, u := range byteUnits {
+		// The trailing B is optional, so that 4M and 4MB are equivalent.
+		if strings.EqualFold(unit, u.symbol) || strings.EqualFold(unit, strings.TrimSuffix(u.symbol, "B")) {
+			size, ok = u.size, true
+			break
+		}
+	}
+	if !ok {
+		return 0, errParse
+	}
+
+	if integer, err := strconv.ParseUint(number, 10, 64); err == nil {
+		hi, lo := bits.Mul64(integer, size)
+		if hi != 0 {
+			return 0, errRange
+		}
+		return lo, nil
+	}
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, numError(err)
+	}
+	if parsed < 0 {
+		return 0, errRange
+	}
+
+	bytes := math.Round(parsed * float64(size))
+	if math.MaxUint64 <= bytes {
+		return 0, errRange
+	}
+	return uint64(bytes), nil
+}
+
+// formatByteSize formats a byte count in whichever unit represents it most concisely.
+func formatByteSize(size uint64) string {
+	formatted := strconv.FormatUint(size, 10) + "B"
+
+	for _, u := range byteUnits {
+		if size < u.size {
+			continue
+		}
+		candidate := formatQuotient(size, u.size) + u.symbol
+		if len(candidate) < len(formatted) {
+			formatted = candidate
+		}
+	}
+
+	return formatted
+}
+
+// formatQuotient formats n/d exactly as a decimal, using integer division so that
+// no precision is lost. The quotient is always finite, as d is a power of 2 or of 10.
+func formatQuotient(n, d uint64) string {
+	var b strings.Builder
+	b.WriteString(strconv.FormatUint(n/d, 10))
+
+	if r := n % d; r != 0 {
+		b.WriteByte('.')
+		for r != 0 {
+			r *= 10
+			b.WriteByte(byte('0' + r/d))
+			r %= d
+		}
+	}
+
+	return b.String()
+}
+
+type ByteSizeValue struct{ Value[uint64] }
+
+func NewByteSizeValue(defValue *uint64, value *uint64, checks ...value.CheckFunc[uint64]) ByteSizeValue {
+	return ByteSizeValue{newValue(defValue, value, checks)}
+}
+
+func (b ByteSizeValue) Set(raw string) error {
+	parsed, err := parseByteSize(raw)
+	*b.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return b.check(parsed)
+}
+
+func (b ByteSizeValue) String() string {
+	return formatByteSize(*ptr.OrZero(b.value))
+}
@@ -13,7 +13,7 @@ func NewDurationValue(defValue *time.Duration, value *time.Duration, checks ...v
 }
 
 func (d DurationValue) Set(raw string) error {
-	parsed, err := time.ParseDuration(raw)
+	parsed, err := parseDuration(raw)
 	*d.value = parsed
 
 	if err != nil {
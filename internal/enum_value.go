@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+)
+
+type EnumValue[T comparable] struct {
+	Value[T]
+	enum value.Enum[T]
+}
+
+func NewEnumValue[T comparable](defValue *T, value *T, enum value.Enum[T], checks ...value.CheckFunc[T]) EnumValue[T] {
+	return EnumValue[T]{newValue(defValue, value, checks), enum}
+}
+
+func (e EnumValue[T]) Set(raw string) error {
+	parsed, err := e.enum.Parse(raw)
+	*e.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return e.check(parsed)
+}
+
+func (e EnumValue[T]) String() string {
+	return e.enum.Format(*ptr.OrZero(e.value))
+}
+
+func (e EnumValue[T]) Choices() []string {
+	return e.enum.Names()
+}
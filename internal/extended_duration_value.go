@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+var (
+	extendedComponent = regexp.MustCompile(`([0-9]*\.?[0-9]+)([a-zµ]+)`)
+	isoDuration       = regexp.MustCompile(`^(?i:P(?:([0-9.,]+)W)?(?:([0-9.,]+)D)?(?:T(?:([0-9.,]+)H)?(?:([0-9.,]+)M)?(?:([0-9.,]+)S)?)?)$`)
+)
+
+// parseExtendedDuration parses a duration as [parseDuration], additionally accepting
+// days (d) and weeks (w) as units, and ISO 8601 durations without years or months.
+func parseExtendedDuration(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+
+	if parsed, err := parseDuration(raw); err == nil {
+		return parsed, nil
+	}
+
+	sign, unsigned := 1., raw
+	if rest, ok := strings.CutPrefix(unsigned, "-"); ok {
+		sign, unsigned = -1, rest
+	} else {
+		unsigned = strings.TrimPrefix(unsigned, "+")
+	}
+
+	if match := isoDuration.FindStringSubmatch(unsigned); match != nil && unsigned != "P" && !strings.EqualFold(unsigned, "PT") {
+		return sumDuration(sign, match[1:], []time.Duration{week, day, time.Hour, time.Minute, time.Second})
+	}
+
+	if unsigned == "" || extendedComponent.ReplaceAllString(unsigned, "") != "" {
+		return 0, errParse
+	}
+
+	var total time.Duration
+	for _, match := range extendedComponent.FindAllStringSubmatch(unsigned, -1) {
+		var unit time.Duration
+		switch match[2] {
+		case "w":
+			unit = week
+		case "d":
+			unit = day
+		default:
+			parsed, err := time.ParseDuration(match[0])
+			if err != nil {
+				return 0, errParse
+			}
+			unit, match[1] = parsed, "1"
+		}
+
+		component, err := sumDuration(1, match[1:2], []time.Duration{unit})
+		if err != nil {
+			return 0, err
+		}
+		if math.MaxInt64-total < component {
+			return 0, errRange
+		}
+		total += component
+	}
+
+	return time.Duration(sign) * total, nil
+}
+
+func sumDuration(sign float64, quantities []string, units []time.Duration) (time.Duration, error) {
+	var total float64
+	for i, quantity := range quantities {
+		if quantity == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.ReplaceAll(quantity, ",", "."), 64)
+		if err != nil {
+			return 0, numError(err)
+		}
+		total += parsed * float64(units[i])
+	}
+
+	if math.MaxInt64 <= total {
+		return 0, errRange
+	}
+	return time.Duration(sign * math.Round(total)), nil
+}
+
+// formatExtendedDuration formats a duration using weeks or days where they apply,
+// omitting any trailing zero units.
+func formatExtendedDuration(d time.Duration) string {
+	sign, magnitude := "", uint64(d)
+	if d < 0 {
+		// Negating the minimum duration would overflow, so the magnitude is taken from d+1.
+		sign, magnitude = "-", uint64(-(d+1))+1
+	}
+
+	if magnitude != 0 && magnitude%uint64(week) == 0 {
+		return sign + strconv.FormatUint(magnitude/uint64(week), 10) + "w"
+	}
+
+	if magnitude < uint64(day) {
+		return sign + trimDuration(time.Duration(magnitude))
+	}
+
+	formatted := sign + strconv.FormatUint(magnitude/uint64(day), 10) + "d"
+	if rest := magnitude % uint64(day); rest != 0 {
+		formatted += trimDuration(time.Duration(rest))
+	}
+	return formatted
+}
+
+func trimDuration(d time.Duration) string {
+	formatted := d.String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = strings.TrimSuffix(formatted, "0s")
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+	return formatted
+}
+
+type ExtendedDurationValue struct{ Value[time.Duration] }
+
+func NewExtendedDurationValue(defValue *time.Duration, value *time.Duration, checks ...value.CheckFunc[time.Duration]) ExtendedDurationValue {
+	return ExtendedDurationValue{newValue(defValue, value, checks)}
+}
+
+func (d ExtendedDurationValue) Set(raw string) error {
+	parsed, err := parseExtendedDuration(raw)
+	*d.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return d.check(parsed)
+}
+
+func (d ExtendedDurationValue) String() string {
+	return formatExtendedDuration(*ptr.OrZero(d.value))
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"strconv"
+)
+
+type Float32Value struct{ Value[float32] }
+
+func NewFloat32Value(defValue *float32, value *float32, checks ...value.CheckFunc[float32]) Float32Value {
+	return Float32Value{newValue(defValue, value, checks)}
+}
+
+func (f Float32Value) Set(raw string) error {
+	parsed, err := parseFloat(raw, 32)
+	*f.value = float32(parsed)
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return f.check(float32(parsed))
+}
+
+func (f Float32Value) String() string {
+	return strconv.FormatFloat(float64(*ptr.OrZero(f.value)), 'g', -1, 32)
+}
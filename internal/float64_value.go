@@ -13,7 +13,7 @@ func NewFloat64Value(defValue *float64, value *float64, checks ...value.CheckFun
 }
 
 func (f Float64Value) Set(raw string) error {
-	parsed, err := strconv.ParseFloat(raw, 64)
+	parsed, err := parseFloat(raw, 64)
 	*f.value = parsed
 
 	if err != nil {
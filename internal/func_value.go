@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+)
+
+type FuncValue[T any] struct {
+	Value[T]
+	parse  value.ParseFunc[T]
+	format value.FormatFunc[T]
+}
+
+func NewFuncValue[T any](defValue *T, value *T, parse value.ParseFunc[T], format value.FormatFunc[T], checks ...value.CheckFunc[T]) FuncValue[T] {
+	return FuncValue[T]{newValue(defValue, value, checks), parse, format}
+}
+
+func (f FuncValue[T]) Set(raw string) error {
+	parsed, err := f.parse(raw)
+	*f.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return f.check(parsed)
+}
+
+func (f FuncValue[T]) String() string {
+	if f.format == nil {
+		return fmt.Sprint(*ptr.OrZero(f.value))
+	}
+
+	return f.format(*ptr.OrZero(f.value))
+}
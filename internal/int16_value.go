@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"strconv"
+)
+
+type Int16Value struct{ Value[int16] }
+
+func NewInt16Value(defValue *int16, value *int16, checks ...value.CheckFunc[int16]) Int16Value {
+	return Int16Value{newValue(defValue, value, checks)}
+}
+
+func (i Int16Value) Set(raw string) error {
+	parsed, err := strconv.ParseInt(raw, 0, 16)
+	*i.value = int16(parsed)
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return i.check(int16(parsed))
+}
+
+func (i Int16Value) String() string {
+	return strconv.FormatInt(int64(*ptr.OrZero(i.value)), 10)
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"strconv"
+)
+
+type Int32Value struct{ Value[int32] }
+
+func NewInt32Value(defValue *int32, value *int32, checks ...value.CheckFunc[int32]) Int32Value {
+	return Int32Value{newValue(defValue, value, checks)}
+}
+
+func (i Int32Value) Set(raw string) error {
+	parsed, err := strconv.ParseInt(raw, 0, 32)
+	*i.value = int32(parsed)
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return i.check(int32(parsed))
+}
+
+func (i Int32Value) String() string {
+	return strconv.FormatInt(int64(*ptr.OrZero(i.value)), 10)
+}
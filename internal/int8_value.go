@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"strconv"
+)
+
+type Int8Value struct{ Value[int8] }
+
+func NewInt8Value(defValue *int8, value *int8, checks ...value.CheckFunc[int8]) Int8Value {
+	return Int8Value{newValue(defValue, value, checks)}
+}
+
+func (i Int8Value) Set(raw string) error {
+	parsed, err := strconv.ParseInt(raw, 0, 8)
+	*i.value = int8(parsed)
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return i.check(int8(parsed))
+}
+
+func (i Int8Value) String() string {
+	return strconv.FormatInt(int64(*ptr.OrZero(i.value)), 10)
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"errors"
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type URLValue struct{ Value[*url.URL] }
+
+func NewURLValue(defValue **url.URL, value **url.URL, checks ...value.CheckFunc[*url.URL]) URLValue {
+	return URLValue{newValue(defValue, value, checks)}
+}
+
+func (u URLValue) Set(raw string) error {
+	parsed, err := url.Parse(raw)
+	*u.value = parsed
+
+	if err != nil || raw == "" {
+		return errParse
+	}
+
+	return u.check(parsed)
+}
+
+func (u URLValue) String() string {
+	if parsed := *ptr.OrZero(u.value); parsed != nil {
+		return parsed.String()
+	}
+
+	return ""
+}
+
+var errPort = errors.New("invalid port")
+
+// joinHostPort normalizes a host and optional port, filling in the default port where missing.
+// The host may be empty, as in listen addresses such as :8080.
+func joinHostPort(raw, defPort string) (string, error) {
+	if raw == "" {
+		return "", errParse
+	}
+
+	host, port, err := net.SplitHostPort(raw)
+	if err != nil {
+		host, port = raw, defPort
+
+		if unbracketed, ok := strings.CutPrefix(host, "["); ok {
+			if host, ok = strings.CutSuffix(unbracketed, "]"); !ok {
+				return "", errParse
+			}
+		}
+
+		// Without a port, only an IPv6 address may contain colons.
+		if strings.Contains(host, ":") {
+			if _, err := netip.ParseAddr(host); err != nil {
+				return "", errParse
+			}
+		}
+	}
+
+	if number, err := strconv.ParseUint(port, 10, 16); err != nil || number == 0 {
+		return "", errPort
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+type HostPortValue struct {
+	Value[string]
+	defPort string
+}
+
+func NewHostPortValue(defValue *string, value *string, defPort string, checks ...value.CheckFunc[string]) HostPortValue {
+	return HostPortValue{newValue(defValue, value, checks), defPort}
+}
+
+func (h HostPortValue) Set(raw string) error {
+	parsed, err := joinHostPort(raw, h.defPort)
+	*h.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return h.check(parsed)
+}
+
+func (h HostPortValue) String() string {
+	return *ptr.OrZero(h.value)
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"errors"
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type siPrefix struct {
+	symbol string
+	scale  float64
+}
+
+// siPrefixes lists the accepted SI prefixes from largest to smallest.
+var siPrefixes = []siPrefix{
+	{"E", 1e18}, {"P", 1e15}, {"T", 1e12}, {"G", 1e9}, {"M", 1e6}, {"k", 1e3},
+	{"", 1},
+	{"m", 1e-3}, {"u", 1e-6}, {"µ", 1e-6}, {"n", 1e-9},
+}
+
+func parseQuantity(raw string) (float64, error) {
+	number, prefix := splitNumber(raw)
+
+	scale, ok := 1., false
+	for _, p := range siPrefixes {
+		if prefix == p.symbol {
+			scale, ok = p.scale, true
+			break
+		}
+	}
+	if !ok {
+		return 0, errParse
+	}
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, numError(err)
+	}
+
+	return parsed * scale, nil
+}
+
+// formatQuantity formats a quantity using the largest SI prefix that
+// keeps its magnitude at least one. Fractional quantities are not prefixed.
+func formatQuantity(quantity float64) string {
+	for _, p := range siPrefixes {
+		if p.scale <= 1 {
+			break
+		}
+		if p.scale <= math.Abs(quantity) {
+			return strconv.FormatFloat(quantity/p.scale, 'g', -1, 64) + p.symbol
+		}
+	}
+
+	return strconv.FormatFloat(quantity, 'g', -1, 64)
+}
+
+type QuantityValue struct{ Value[float64] }
+
+func NewQuantityValue(defValue *float64, value *float64, checks ...value.CheckFunc[float64]) QuantityValue {
+	return QuantityValue{newValue(defValue, value, checks)}
+}
+
+func (q QuantityValue) Set(raw string) error {
+	parsed, err := parseQuantity(raw)
+	*q.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return q.check(parsed)
+}
+
+func (q QuantityValue) String() string {
+	return formatQuantity(*ptr.OrZero(q.value))
+}
+
+var errPercent = errors.New("missing % suffix")
+
+type PercentValue struct{ Value[float64] }
+
+func NewPercentValue(defValue *float64, value *float64, checks ...value.CheckFunc[float64]) PercentValue {
+	return PercentValue{newValue(defValue, value, checks)}
+}
+
+func (p PercentValue) Set(raw string) error {
+	number, percent := strings.CutSuffix(strings.TrimSpace(raw), "%")
+	if !percent {
+		return errPercent
+	}
+
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	parsed /= 100
+	*p.value = parsed
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return p.check(parsed)
+}
+
+func (p PercentValue) String() string {
+	return strconv.FormatFloat(*ptr.OrZero(p.value)*100, 'g', 15, 64) + "%"
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"io"
+	"os"
+	"strings"
+)
+
+// readSecret resolves a raw secret, which may instead name a file
+// to read prefixed with @, or be - to read from stdin. A literal
+// leading @ is given as @@.
+func readSecret(raw string, stdin io.Reader) (string, error) {
+	if raw == "-" {
+		read, err := io.ReadAll(stdin)
+		return strings.TrimRight(string(read), "\r\n"), err
+	}
+
+	if path, ok := strings.CutPrefix(raw, "@"); ok && !strings.HasPrefix(path, "@") {
+		read, err := os.ReadFile(path)
+		return strings.TrimRight(string(read), "\r\n"), err
+	}
+
+	return strings.TrimPrefix(raw, "@"), nil
+}
+
+type SecretValue struct {
+	Value[string]
+	stdin *io.Reader
+}
+
+func NewSecretValue(defValue *string, value *string, checks ...value.CheckFunc[string]) SecretValue {
+	stdin := io.Reader(os.Stdin)
+	return SecretValue{newValue(defValue, value, checks), &stdin}
+}
+
+func (s SecretValue) Set(raw string) error {
+	parsed, err := readSecret(raw, *s.stdin)
+	*s.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return s.check(parsed)
+}
+
+// SetLiteral sets the secret as given, without reading any file or stdin.
+func (s SecretValue) SetLiteral(raw string) error {
+	*s.value = raw
+	return s.check(raw)
+}
+
+// SetStdin sets the reader from which the secret is read when given as -.
+func (s SecretValue) SetStdin(stdin io.Reader) {
+	*s.stdin = stdin
+}
+
+func (s SecretValue) String() string {
+	if s.value == nil || *s.value == "" {
+		return ""
+	}
+
+	return value.Redacted
+}
+
+func (s SecretValue) IsSecret() bool {
+	return true
+}
@@ -0,0 +1,299 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/command/value"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseScalar parses any of the basic scalar types, using the same rules as their individual values.
+func parseScalar[T value.Scalar](raw string) (T, error) {
+	var parsed T
+	target := reflect.ValueOf(&parsed).Elem()
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if target.Type() == reflect.TypeFor[time.Duration]() {
+			duration, err := parseDuration(raw)
+			if err != nil {
+				return parsed, errParse
+			}
+			target.SetInt(int64(duration))
+			break
+		}
+		integer, err := strconv.ParseInt(raw, 0, target.Type().Bits())
+		if err != nil {
+			return parsed, numError(err)
+		}
+		target.SetInt(integer)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		integer, err := strconv.ParseUint(raw, 0, target.Type().Bits())
+		if err != nil {
+			return parsed, numError(err)
+		}
+		target.SetUint(integer)
+	case reflect.Float32, reflect.Float64:
+		float, err := parseFloat(raw, target.Type().Bits())
+		if err != nil {
+			return parsed, numError(err)
+		}
+		target.SetFloat(float)
+	case reflect.String:
+		target.SetString(raw)
+	}
+
+	return parsed, nil
+}
+
+type ListValue[T value.Scalar] struct{ Value[[]T] }
+
+func NewListValue[T value.Scalar](defValue *[]T, value *[]T, checks ...value.CheckFunc[T]) ListValue[T] {
+	return ListValue[T]{newValue(defValue, value, each(checks))}
+}
+
+// each lifts element checks to apply to every element of a list.
+func each[T any](checks []value.CheckFunc[T]) constraint[[]T] {
+	return constraint[[]T]{value.Describe(func(list []T) error {
+		for _, element := range list {
+			if err := constraint[T](checks).check(element); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, value.Constraints(checks...)...)}
+}
+
+func (l ListValue[T]) Set(raw string) error {
+	var parsed []T
+	var err error
+
+	if raw = strings.TrimSpace(raw); raw != "" {
+		for _, element := range strings.Split(raw, ",") {
+			var item T
+			if item, err = parseScalar[T](strings.TrimSpace(element)); err != nil {
+				break
+			}
+			parsed = append(parsed, item)
+		}
+	}
+	*l.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return l.check(parsed)
+}
+
+func (l ListValue[T]) String() string {
+	if l.value == nil {
+		return ""
+	}
+
+	elements := make([]string, 0, len(*l.value))
+	for _, element := range *l.value {
+		elements = append(elements, fmt.Sprint(element))
+	}
+	return strings.Join(elements, ",")
+}
+
+var errBounds = errors.New("lower bound exceeds upper bound")
+
+type RangeValue[T value.Number] struct{ Value[value.Range[T]] }
+
+func NewRangeValue[T value.Number](defValue *value.Range[T], value *value.Range[T], checks ...value.CheckFunc[T]) RangeValue[T] {
+	return RangeValue[T]{newValue(defValue, value, bounds(checks))}
+}
+
+// bounds lifts checks to apply to every value in a range of integers, where an omitted bound extends
+// to the limit of the type. The values of a floating-point range cannot be enumerated, so only the bounds
+// present are checked.
+func bounds[T value.Number](checks []value.CheckFunc[T]) constraint[value.Range[T]] {
+	return constraint[value.Range[T]]{value.Describe(func(r value.Range[T]) error {
+		if len(checks) == 0 {
+			return nil
+		}
+		if T(1)/T(2) == 0 {
+			return checkIntegers(r, checks)
+		}
+
+		if r.HasLo {
+			if err := constraint[T](checks).check(r.Lo); err != nil {
+				return err
+			}
+		}
+		if r.HasHi {
+			if err := constraint[T](checks).check(r.Hi); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, value.Constraints(checks...)...)}
+}
+
+// maxRangeChecks is the largest number of values which are checked in a range of integers.
+const maxRangeChecks = 1 << 20
+
+var errRangeSize = fmt.Errorf("range exceeds %d values to check", maxRangeChecks)
+
+// checkIntegers checks every value of a range of integers, beginning with the lowest and highest.
+func checkIntegers[T value.Number](r value.Range[T], checks []value.CheckFunc[T]) error {
+	var zero T
+	lo, hi := zero, zero-1
+	if zero-1 < zero {
+		bits := reflect.TypeFor[T]().Bits()
+		hi = T(uint64(1)<<(bits-1) - 1)
+		lo = -hi - 1
+	}
+
+	if r.HasLo {
+		lo = r.Lo
+	}
+	if r.HasHi {
+		hi = r.Hi
+	}
+	if r.HasHi && r.Exclusive {
+		if hi == lo {
+			return nil
+		}
+		hi--
+	}
+
+	for _, v := range []T{lo, hi} {
+		if err := constraint[T](checks).check(v); err != nil {
+			return err
+		}
+	}
+
+	// The difference wraps correctly even between signed bounds.
+	if maxRangeChecks <= uint64(hi)-uint64(lo) {
+		return errRangeSize
+	}
+	for v := lo + 1; v < hi; v++ {
+		if err := constraint[T](checks).check(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRange parses a range from lo-hi, lo..hi, lo:hi, or lo..<hi, where
+// either bound may be omitted except when separated by a dash, or from a single value.
+func parseRange[T value.Number](raw string) (value.Range[T], error) {
+	raw = strings.TrimSpace(raw)
+
+	// Try every occurrence of each separator, since a dash may also be a sign.
+	for _, separator := range []string{"..<", "..", ":", "-"} {
+		for i := range len(raw) {
+			if !strings.HasPrefix(raw[i:], separator) {
+				continue
+			}
+			if r, err := parseBounds[T](raw[:i], raw[i+len(separator):], separator); err != errParse {
+				return r, err
+			}
+		}
+	}
+
+	// A single value is a range containing only itself.
+	single, err := parseScalar[T](raw)
+	return value.Range[T]{Lo: single, Hi: single, HasLo: true, HasHi: true}, err
+}
+
+func parseBounds[T value.Number](lo, hi, separator string) (r value.Range[T], err error) {
+	r.Exclusive = separator == "..<"
+
+	if lo, hi = strings.TrimSpace(lo), strings.TrimSpace(hi); separator == "-" && (lo == "" || hi == "") {
+		return r, errParse
+	}
+
+	if r.HasLo = lo != ""; r.HasLo {
+		if r.Lo, err = parseScalar[T](lo); err != nil {
+			return r, err
+		}
+	}
+	if r.HasHi = hi != ""; r.HasHi {
+		if r.Hi, err = parseScalar[T](hi); err != nil {
+			return r, err
+		}
+	}
+
+	if r.HasLo && r.HasHi && r.Hi < r.Lo {
+		return r, errBounds
+	}
+	return r, nil
+}
+
+func (r RangeValue[T]) Set(raw string) error {
+	parsed, err := parseRange[T](raw)
+	*r.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return r.check(parsed)
+}
+
+func (r RangeValue[T]) String() string {
+	if r.value == nil {
+		return ""
+	}
+
+	return r.value.String()
+}
+
+var errKey = errors.New("missing key")
+
+type PairValue[K, V value.Scalar] struct{ Value[value.Pair[K, V]] }
+
+func NewPairValue[K, V value.Scalar](defValue *value.Pair[K, V], value *value.Pair[K, V], checks ...value.CheckFunc[V]) PairValue[K, V] {
+	return PairValue[K, V]{newValue(defValue, value, values[K](checks))}
+}
+
+// values lifts checks to apply to the value of a pair, and to its key when of the same type.
+func values[K, V any](checks []value.CheckFunc[V]) constraint[value.Pair[K, V]] {
+	return constraint[value.Pair[K, V]]{value.Describe(func(pair value.Pair[K, V]) error {
+		if key, ok := any(pair.Key).(V); ok {
+			if err := constraint[V](checks).check(key); err != nil {
+				return err
+			}
+		}
+		return constraint[V](checks).check(pair.Value)
+	}, value.Constraints(checks...)...)}
+}
+
+func (p PairValue[K, V]) Set(raw string) error {
+	var parsed value.Pair[K, V]
+
+	key, val, ok := strings.Cut(raw, "=")
+	if !ok {
+		return errParse
+	}
+	if key = strings.TrimSpace(key); key == "" {
+		return errKey
+	}
+
+	var err error
+	if parsed.Key, err = parseScalar[K](key); err == nil {
+		parsed.Value, err = parseScalar[V](strings.TrimSpace(val))
+	}
+	*p.value = parsed
+
+	if err != nil {
+		return err
+	}
+
+	return p.check(parsed)
+}
+
+func (p PairValue[K, V]) String() string {
+	if p.value == nil {
+		return ""
+	}
+
+	return p.value.String()
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+)
+
+type TextValue[T any, P value.TextPointer[T]] struct{ Value[T] }
+
+func NewTextValue[T any, P value.TextPointer[T]](defValue *T, value *T, checks ...value.CheckFunc[T]) TextValue[T, P] {
+	return TextValue[T, P]{newValue(defValue, value, checks)}
+}
+
+func (t TextValue[T, P]) Set(raw string) error {
+	if err := P(t.value).UnmarshalText([]byte(raw)); err != nil {
+		return err
+	}
+
+	return t.check(*t.value)
+}
+
+func (t TextValue[T, P]) String() string {
+	text, err := P(ptr.OrZero(t.value)).MarshalText()
+	if err != nil {
+		return ""
+	}
+
+	return string(text)
+}
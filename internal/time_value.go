@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"time"
+)
+
+type TimeValue struct {
+	Value[time.Time]
+	parser value.TimeParser
+}
+
+func NewTimeValue(defValue *time.Time, value *time.Time, checks ...value.CheckFunc[time.Time]) TimeValue {
+	return TimeValue{Value: newValue(defValue, value, checks)}
+}
+
+func NewTimeLayoutsValue(defValue *time.Time, value *time.Time, layouts []string, checks ...value.CheckFunc[time.Time]) TimeValue {
+	t := NewTimeValue(defValue, value, checks...)
+	t.parser.Layouts = layouts
+	return t
+}
+
+func (t TimeValue) Set(raw string) error {
+	parsed, err := t.parser.Parse(raw)
+	*t.value = parsed
+
+	if err != nil {
+		return errParse
+	}
+
+	return t.check(parsed)
+}
+
+func (t TimeValue) String() string {
+	return t.parser.Format(*ptr.OrZero(t.value))
+}
+
+type DateValue struct {
+	Value[time.Time]
+	parser value.DateParser
+}
+
+func NewDateValue(defValue *time.Time, value *time.Time, checks ...value.CheckFunc[time.Time]) DateValue {
+	return DateValue{Value: newValue(defValue, value, checks)}
+}
+
+func (d DateValue) Set(raw string) error {
+	parsed, err := d.parser.Parse(raw)
+	*d.value = parsed
+
+	if err != nil {
+		return errParse
+	}
+
+	return d.check(parsed)
+}
+
+func (d DateValue) String() string {
+	return d.parser.Format(*ptr.OrZero(d.value))
+}
+
+type LocationValue struct{ Value[*time.Location] }
+
+func NewLocationValue(defValue **time.Location, value **time.Location, checks ...value.CheckFunc[*time.Location]) LocationValue {
+	return LocationValue{newValue(defValue, value, checks)}
+}
+
+func (l LocationValue) Set(raw string) error {
+	parsed, err := time.LoadLocation(raw)
+	*l.value = parsed
+
+	if err != nil {
+		return errParse
+	}
+
+	return l.check(parsed)
+}
+
+func (l LocationValue) String() string {
+	if location := *ptr.OrZero(l.value); location != nil {
+		return location.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"strconv"
+)
+
+type Uint16Value struct{ Value[uint16] }
+
+func NewUint16Value(defValue *uint16, value *uint16, checks ...value.CheckFunc[uint16]) Uint16Value {
+	return Uint16Value{newValue(defValue, value, checks)}
+}
+
+func (u Uint16Value) Set(raw string) error {
+	parsed, err := strconv.ParseUint(raw, 0, 16)
+	*u.value = uint16(parsed)
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return u.check(uint16(parsed))
+}
+
+func (u Uint16Value) String() string {
+	return strconv.FormatUint(uint64(*ptr.OrZero(u.value)), 10)
+}
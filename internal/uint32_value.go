@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"strconv"
+)
+
+type Uint32Value struct{ Value[uint32] }
+
+func NewUint32Value(defValue *uint32, value *uint32, checks ...value.CheckFunc[uint32]) Uint32Value {
+	return Uint32Value{newValue(defValue, value, checks)}
+}
+
+func (u Uint32Value) Set(raw string) error {
+	parsed, err := strconv.ParseUint(raw, 0, 32)
+	*u.value = uint32(parsed)
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return u.check(uint32(parsed))
+}
+
+func (u Uint32Value) String() string {
+	return strconv.FormatUint(uint64(*ptr.OrZero(u.value)), 10)
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/ptr"
+	"strconv"
+)
+
+type Uint8Value struct{ Value[uint8] }
+
+func NewUint8Value(defValue *uint8, value *uint8, checks ...value.CheckFunc[uint8]) Uint8Value {
+	return Uint8Value{newValue(defValue, value, checks)}
+}
+
+func (u Uint8Value) Set(raw string) error {
+	parsed, err := strconv.ParseUint(raw, 0, 8)
+	*u.value = uint8(parsed)
+
+	if err != nil {
+		return numError(err)
+	}
+
+	return u.check(uint8(parsed))
+}
+
+func (u Uint8Value) String() string {
+	return strconv.FormatUint(uint64(*ptr.OrZero(u.value)), 10)
+}
@@ -3,7 +3,9 @@ package internal
 import (
 	"errors"
 	"github.com/michaeljpetter/command/value"
+	"regexp"
 	"strconv"
+	"time"
 )
 
 var (
@@ -24,6 +26,45 @@ func numError(err error) error {
 	return err
 }
 
+// prefixed matches integers with an explicit base prefix,
+// along with the character preceding them, if any.
+var prefixed = regexp.MustCompile(`(^|[^0-9.])([+-]?0[bBoOxX][0-9a-fA-F_]+)`)
+
+func hasPrefix(raw string) bool {
+	return prefixed.FindString(raw) == raw
+}
+
+// parseFloat parses a float with the given bit size, additionally accepting
+// integers with a binary, octal, or hexadecimal prefix.
+func parseFloat(raw string, bitSize int) (float64, error) {
+	parsed, err := strconv.ParseFloat(raw, bitSize)
+	if err == nil || !hasPrefix(raw) {
+		return parsed, err
+	}
+
+	integer, err := strconv.ParseInt(raw, 0, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(strconv.FormatInt(integer, 10), bitSize)
+}
+
+// parseDuration parses a duration as [time.ParseDuration], additionally accepting
+// integer quantities with a binary, octal, or hexadecimal prefix.
+func parseDuration(raw string) (time.Duration, error) {
+	return time.ParseDuration(
+		prefixed.ReplaceAllStringFunc(raw, func(match string) string {
+			groups := prefixed.FindStringSubmatch(match)
+			integer, err := strconv.ParseInt(groups[2], 0, 64)
+			if err != nil {
+				return match
+			}
+			return groups[1] + strconv.FormatInt(integer, 10)
+		}),
+	)
+}
+
 type constraint[T any] []value.CheckFunc[T]
 
 func (c constraint[T]) check(value T) error {
@@ -56,3 +97,7 @@ func (v Value[_]) Get() any {
 func (v Value[_]) Required() bool {
 	return v.required
 }
+
+func (v Value[T]) Constraints() []value.Constraint {
+	return value.Constraints(v.constraint...)
+}
@@ -0,0 +1,37 @@
+package internal
+
+import "strings"
+
+// Wrap splits text into lines which, once indented by the given number of columns,
+// fit within the given width. Existing line breaks are kept, and words longer than
+// the available width are placed alone on their own line.
+// When width is not positive, text is split only at existing line breaks.
+func Wrap(text string, width, indent int) []string {
+	if width <= 0 {
+		return strings.Split(text, "\n")
+	}
+
+	available := max(width-indent, 1)
+	lines := make([]string, 0)
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if 0 == len(words) {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := words[0]
+		for _, word := range words[1:] {
+			if available < len(line)+1+len(word) {
+				lines = append(lines, line)
+				line = word
+			} else {
+				line += " " + word
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
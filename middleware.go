@@ -0,0 +1,106 @@
+package command
+
+import (
+	"fmt"
+	"github.com/michaeljpetter/command/flag"
+	"log/slog"
+	"time"
+)
+
+// Middleware wraps a [HandlerFunc], such as to run code around it.
+type Middleware func(HandlerFunc) HandlerFunc
+
+// Use adds middleware to wrap the handler of every subcommand below this command, at any depth.
+// Middleware added first is outermost, and middleware added by ancestors wraps that added by descendants.
+//
+// As the handler of a subcommand includes running any subcommands of its own, middleware wraps each
+// subcommand along the way. Once the handler has returned, the subcommand which was ultimately executed
+// is the one which defines no subcommands of its own, as reported by [Command.HasSubcommands].
+func (c *Command) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// fail reports an error raised around the handler of a subcommand, which is then
+// returned by the parent command as though it were a parse error.
+func (c *Command) fail(err error) {
+	fmt.Fprintln(c.Output(), err)
+	c.failure = c.handle(err)
+}
+
+// Recover is middleware which recovers from a panic in a handler,
+// reporting it as an error according to the error handling of the subcommand.
+func Recover(next HandlerFunc) HandlerFunc {
+	return func(b Bound) {
+		defer func() {
+			if r := recover(); r != nil {
+				b.fail(fmt.Errorf("panic: %v", r))
+			}
+		}()
+
+		next(b)
+	}
+}
+
+// Timing returns middleware which reports how long the subcommand which was ultimately executed
+// took to run, including parsing its arguments.
+func Timing(report func(*Command, time.Duration)) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(b Bound) {
+			start := time.Now()
+			next(b)
+
+			if !b.HasSubcommands() {
+				report(b.Command, time.Since(start))
+			}
+		}
+	}
+}
+
+// Audit returns middleware which logs the subcommand which was ultimately executed, once it has run,
+// with the full command path, the values of the flags and positional parameters which were given,
+// and any remaining arguments. The values of secrets are redacted.
+func Audit(logger *slog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(b Bound) {
+			defer func() {
+				if !b.HasSubcommands() && b.Parsed() {
+					logger.Info("command", "command", b.Name(), "args", b.auditArgs())
+				}
+			}()
+
+			next(b)
+		}
+	}
+}
+
+// auditArgs returns the flags and positional parameters which were explicitly given,
+// followed by any remaining arguments, with the values of secrets redacted.
+func (c *Command) auditArgs() []string {
+	var args []string
+	c.FlagSet.Visit(func(f *flag.Flag) {
+		args = append(args, "-"+f.Name+"="+flag.Display(f.Value, f.Value.String()))
+	})
+
+	for _, positional := range c.positional {
+		if positional.set {
+			args = append(args, flag.Display(positional.value, positional.value.String()))
+		}
+	}
+
+	return append(args, c.Args()...)
+}
+
+// Gate returns middleware which runs a subcommand only if it is enabled, such as by a feature flag.
+// A subcommand which is not enabled is reported as an error.
+func Gate(enabled func(*Command) bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(b Bound) {
+			if !enabled(b.Command) {
+				b.fail(fmt.Errorf("command not enabled: %s", b.Name()))
+				return
+			}
+
+			next(b)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/fp"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// pluginPrefix returns the prefix of the executable names of the plugins of this command,
+// such as "prog-cluster-" for command "prog cluster".
+func (c *Command) pluginPrefix() string {
+	return strings.ReplaceAll(c.Name(), " ", "-") + "-"
+}
+
+// pluginDirs returns the directories searched for plugins, in order.
+func (c *Command) pluginDirs() []string {
+	if 0 < len(c.PluginDirs) {
+		return c.PluginDirs
+	}
+	return filepath.SplitList(os.Getenv("PATH"))
+}
+
+// lookPlugin finds the executable of the named plugin, reporting whether it was found.
+func (c *Command) lookPlugin(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+
+	for _, dir := range c.pluginDirs() {
+		if dir == "" {
+			continue
+		}
+		for _, file := range executableFiles(c.pluginPrefix() + name) {
+			if path := filepath.Join(dir, file); isExecutable(path) {
+				return path, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// plugins returns the executable of each plugin found by name, excluding any which are hidden
+// by subcommands, or which belong to them. The first found in the order searched takes precedence.
+func (c *Command) plugins() map[string]string {
+	plugins := make(map[string]string)
+	if !c.Plugins {
+		return plugins
+	}
+
+	usages := c.subcommandUsages()
+	prefix := c.pluginPrefix()
+
+	for _, dir := range c.pluginDirs() {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			file, ok := executableName(entry.Name())
+			if !ok {
+				continue
+			}
+			name, ok := strings.CutPrefix(file, prefix)
+			if !ok || name == "" {
+				continue
+			}
+			parent, _, _ := strings.Cut(name, "-")
+			if _, ok := usages[parent]; ok {
+				continue
+			}
+			if _, ok := plugins[name]; ok {
+				continue
+			}
+			if path := filepath.Join(dir, entry.Name()); isExecutable(path) {
+				plugins[name] = path
+			}
+		}
+	}
+
+	return plugins
+}
+
+// describePlugins describes the plugins found, sorted by name, if this command defines subcommands.
+func (c *Command) describePlugins() []SubcommandDescription {
+	if !c.HasSubcommands() {
+		return nil
+	}
+
+	var described []SubcommandDescription
+	plugins := c.plugins()
+	for _, name := range slices.Sorted(maps.Keys(plugins)) {
+		described = append(described, SubcommandDescription{Name: name, Usage: plugins[name]})
+	}
+	return described
+}
+
+// printPlugins prints the plugins found, under a separate heading, if there are any.
+func (c *Command) printPlugins() {
+	plugins := c.plugins()
+	if len(plugins) == 0 {
+		return
+	}
+
+	names := slices.Sorted(maps.Keys(plugins))
+	longest := fp.MaxOf(fp.StringLen, 4)(slices.Values(names))
+
+	fmt.Fprint(c.Output(), "\nPlugins:\n")
+	for _, name := range names {
+		fmt.Fprint(c.Output(), formatEntry(c.WrapWidth(), longest, name, plugins[name]))
+	}
+}
+
+// runPlugin runs the executable of the named plugin with the given arguments, and with variables
+// in its environment describing this command. Its input and output are those of the shell being run,
+// or otherwise the standard input and output of the process, and its errors go to the output of this command.
+// A plugin which fails is expected to have reported its own error, and the failure is returned
+// as an [*exec.ExitError], carrying its exit status.
+func (c *Command) runPlugin(name, path string, args []string) error {
+	executable, _ := os.Executable()
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, c.Output()
+	if c.session != nil {
+		cmd.Stdin, cmd.Stdout = c.session.in, c.session.out
+	}
+	cmd.Env = append(os.Environ(),
+		"COMMAND_NAME="+c.Name(),
+		"COMMAND_PLUGIN="+name,
+		"COMMAND_EXECUTABLE="+executable,
+	)
+
+	err := cmd.Run()
+
+	var exit *exec.ExitError
+	if err != nil && !errors.As(err, &exit) {
+		err = fmt.Errorf("plugin %s: %w", name, err)
+		fmt.Fprintln(c.Output(), err)
+	}
+
+	if err != nil {
+		return reported{err}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package command
+
+import (
+	"os"
+)
+
+// executableFiles returns the names of the files which may run the named executable, in order of precedence.
+func executableFiles(name string) []string {
+	return []string{name}
+}
+
+// executableName returns the name by which a file is run as an executable,
+// reporting whether the file may be executable by its name.
+func executableName(file string) (string, bool) {
+	return file, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode()&0111 != 0
+}
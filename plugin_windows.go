@@ -0,0 +1,48 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// executableExts returns the extensions of executable files listed by PATHEXT, in lower case,
+// defaulting to those assumed by os/exec when unset.
+func executableExts() []string {
+	var exts []string
+	for _, ext := range filepath.SplitList(os.Getenv("PATHEXT")) {
+		if ext != "" && ext[0] == '.' {
+			exts = append(exts, strings.ToLower(ext))
+		}
+	}
+	if len(exts) == 0 {
+		return []string{".com", ".exe", ".bat", ".cmd"}
+	}
+	return exts
+}
+
+// executableFiles returns the names of the files which may run the named executable, in order of precedence.
+func executableFiles(name string) []string {
+	var files []string
+	for _, ext := range executableExts() {
+		files = append(files, name+ext)
+	}
+	return files
+}
+
+// executableName returns the name by which a file is run as an executable, without its extension,
+// reporting whether the file may be executable by its name.
+func executableName(file string) (string, bool) {
+	ext := filepath.Ext(file)
+	if ext == "" || !slices.Contains(executableExts(), strings.ToLower(ext)) {
+		return "", false
+	}
+	return strings.TrimSuffix(file, ext), true
+}
+
+// isExecutable reports whether the path is a regular file, its extension having been checked by name.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
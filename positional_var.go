@@ -3,6 +3,8 @@ package command
 import (
 	"github.com/michaeljpetter/command/internal"
 	"github.com/michaeljpetter/command/value"
+	"net/netip"
+	"net/url"
 	"time"
 )
 
@@ -24,6 +26,60 @@ func (c *Command) PositionalInt(name string, value *int, usage string, checks ..
 	return p
 }
 
+// PositionalInt8Var defines a positional int8 parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalInt8Var(p *int8, name string, value *int8, usage string, checks ...value.CheckFunc[int8]) {
+	c.PositionalVar(internal.NewInt8Value(value, p, checks...), name, usage)
+}
+
+// PositionalInt8 defines a positional int8 parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalInt8(name string, value *int8, usage string, checks ...value.CheckFunc[int8]) *int8 {
+	p := new(int8)
+	c.PositionalInt8Var(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalInt16Var defines a positional int16 parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalInt16Var(p *int16, name string, value *int16, usage string, checks ...value.CheckFunc[int16]) {
+	c.PositionalVar(internal.NewInt16Value(value, p, checks...), name, usage)
+}
+
+// PositionalInt16 defines a positional int16 parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalInt16(name string, value *int16, usage string, checks ...value.CheckFunc[int16]) *int16 {
+	p := new(int16)
+	c.PositionalInt16Var(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalInt32Var defines a positional int32 parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalInt32Var(p *int32, name string, value *int32, usage string, checks ...value.CheckFunc[int32]) {
+	c.PositionalVar(internal.NewInt32Value(value, p, checks...), name, usage)
+}
+
+// PositionalInt32 defines a positional int32 parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalInt32(name string, value *int32, usage string, checks ...value.CheckFunc[int32]) *int32 {
+	p := new(int32)
+	c.PositionalInt32Var(p, name, value, usage, checks...)
+	return p
+}
+
 // PositionalInt64Var defines a positional int64 parameter with the given name, default value, usage, and checks.
 // The pointer p defines the location to receive the parsed value.
 //
@@ -60,6 +116,60 @@ func (c *Command) PositionalUint(name string, value *uint, usage string, checks
 	return p
 }
 
+// PositionalUint8Var defines a positional uint8 parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalUint8Var(p *uint8, name string, value *uint8, usage string, checks ...value.CheckFunc[uint8]) {
+	c.PositionalVar(internal.NewUint8Value(value, p, checks...), name, usage)
+}
+
+// PositionalUint8 defines a positional uint8 parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalUint8(name string, value *uint8, usage string, checks ...value.CheckFunc[uint8]) *uint8 {
+	p := new(uint8)
+	c.PositionalUint8Var(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalUint16Var defines a positional uint16 parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalUint16Var(p *uint16, name string, value *uint16, usage string, checks ...value.CheckFunc[uint16]) {
+	c.PositionalVar(internal.NewUint16Value(value, p, checks...), name, usage)
+}
+
+// PositionalUint16 defines a positional uint16 parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalUint16(name string, value *uint16, usage string, checks ...value.CheckFunc[uint16]) *uint16 {
+	p := new(uint16)
+	c.PositionalUint16Var(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalUint32Var defines a positional uint32 parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalUint32Var(p *uint32, name string, value *uint32, usage string, checks ...value.CheckFunc[uint32]) {
+	c.PositionalVar(internal.NewUint32Value(value, p, checks...), name, usage)
+}
+
+// PositionalUint32 defines a positional uint32 parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalUint32(name string, value *uint32, usage string, checks ...value.CheckFunc[uint32]) *uint32 {
+	p := new(uint32)
+	c.PositionalUint32Var(p, name, value, usage, checks...)
+	return p
+}
+
 // PositionalUint64Var defines a positional uint64 parameter with the given name, default value, usage, and checks.
 // The pointer p defines the location to receive the parsed value.
 //
@@ -78,6 +188,24 @@ func (c *Command) PositionalUint64(name string, value *uint64, usage string, che
 	return p
 }
 
+// PositionalFloat32Var defines a positional float32 parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalFloat32Var(p *float32, name string, value *float32, usage string, checks ...value.CheckFunc[float32]) {
+	c.PositionalVar(internal.NewFloat32Value(value, p, checks...), name, usage)
+}
+
+// PositionalFloat32 defines a positional float32 parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalFloat32(name string, value *float32, usage string, checks ...value.CheckFunc[float32]) *float32 {
+	p := new(float32)
+	c.PositionalFloat32Var(p, name, value, usage, checks...)
+	return p
+}
+
 // PositionalFloat64Var defines a positional float64 parameter with the given name, default value, usage, and checks.
 // The pointer p defines the location to receive the parsed value.
 //
@@ -131,3 +259,499 @@ func (c *Command) PositionalDuration(name string, value *time.Duration, usage st
 	c.PositionalDurationVar(p, name, value, usage, checks...)
 	return p
 }
+
+// PositionalExtendedDurationVar defines a positional extended [time.Duration] parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// In addition to the syntax of [time.ParseDuration], extended durations accept days (d) and weeks (w) as units,
+// such as 7d or 1d12h, as well as ISO 8601 durations without years or months, such as P1DT2H.
+// They are displayed using days and weeks where they apply.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalExtendedDurationVar(p *time.Duration, name string, value *time.Duration, usage string, checks ...value.CheckFunc[time.Duration]) {
+	c.PositionalVar(internal.NewExtendedDurationValue(value, p, checks...), name, usage)
+}
+
+// PositionalExtendedDuration defines a positional extended [time.Duration] parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// In addition to the syntax of [time.ParseDuration], extended durations accept days (d) and weeks (w) as units,
+// such as 7d or 1d12h, as well as ISO 8601 durations without years or months, such as P1DT2H.
+// They are displayed using days and weeks where they apply.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalExtendedDuration(name string, value *time.Duration, usage string, checks ...value.CheckFunc[time.Duration]) *time.Duration {
+	p := new(time.Duration)
+	c.PositionalExtendedDurationVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalByteSizeVar defines a positional byte size parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Byte sizes accept an optional SI (KB, MB, ...) or IEC (KiB, MiB, ...) unit, with the trailing B optional,
+// and are displayed in whichever unit is most concise.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalByteSizeVar(p *uint64, name string, value *uint64, usage string, checks ...value.CheckFunc[uint64]) {
+	c.PositionalVar(internal.NewByteSizeValue(value, p, checks...), name, usage)
+}
+
+// PositionalByteSize defines a positional byte size parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Byte sizes accept an optional SI (KB, MB, ...) or IEC (KiB, MiB, ...) unit, with the trailing B optional,
+// and are displayed in whichever unit is most concise.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalByteSize(name string, value *uint64, usage string, checks ...value.CheckFunc[uint64]) *uint64 {
+	p := new(uint64)
+	c.PositionalByteSizeVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalQuantityVar defines a positional quantity parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Quantities accept an optional SI prefix (k, M, G, ..., m, u, n),
+// and are displayed with the largest prefix that applies.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalQuantityVar(p *float64, name string, value *float64, usage string, checks ...value.CheckFunc[float64]) {
+	c.PositionalVar(internal.NewQuantityValue(value, p, checks...), name, usage)
+}
+
+// PositionalQuantity defines a positional quantity parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Quantities accept an optional SI prefix (k, M, G, ..., m, u, n),
+// and are displayed with the largest prefix that applies.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalQuantity(name string, value *float64, usage string, checks ...value.CheckFunc[float64]) *float64 {
+	p := new(float64)
+	c.PositionalQuantityVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalPercentVar defines a positional percentage parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Percentages accept a number suffixed with %, which is scaled to a fraction,
+// such that 75% is 0.75, and are displayed as a percentage. A bare number is rejected as ambiguous.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalPercentVar(p *float64, name string, value *float64, usage string, checks ...value.CheckFunc[float64]) {
+	c.PositionalVar(internal.NewPercentValue(value, p, checks...), name, usage)
+}
+
+// PositionalPercent defines a positional percentage parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Percentages accept a number suffixed with %, which is scaled to a fraction,
+// such that 75% is 0.75, and are displayed as a percentage. A bare number is rejected as ambiguous.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalPercent(name string, value *float64, usage string, checks ...value.CheckFunc[float64]) *float64 {
+	p := new(float64)
+	c.PositionalPercentVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalTimeVar defines a positional [time.Time] parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Times are parsed by a default [value.TimeParser], accepting RFC 3339 and relative expressions such as now-2h.
+// Use [Command.PositionalTimeLayoutsVar] for other layouts, or [PositionalFuncVar] with a configured [value.TimeParser].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalTimeVar(p *time.Time, name string, value *time.Time, usage string, checks ...value.CheckFunc[time.Time]) {
+	c.PositionalVar(internal.NewTimeValue(value, p, checks...), name, usage)
+}
+
+// PositionalTime defines a positional [time.Time] parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Times are parsed by a default [value.TimeParser], accepting RFC 3339 and relative expressions such as now-2h.
+// Use [Command.PositionalTimeLayoutsVar] for other layouts, or [PositionalFuncVar] with a configured [value.TimeParser].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalTime(name string, value *time.Time, usage string, checks ...value.CheckFunc[time.Time]) *time.Time {
+	p := new(time.Time)
+	c.PositionalTimeVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalTimeLayoutsVar defines a positional [time.Time] parameter with the given name, default value, layouts, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Times are parsed by a [value.TimeParser] with the given layouts, which are tried in order,
+// the first also being used to display the default, and relative expressions such as now-2h.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalTimeLayoutsVar(p *time.Time, name string, value *time.Time, layouts []string, usage string, checks ...value.CheckFunc[time.Time]) {
+	c.PositionalVar(internal.NewTimeLayoutsValue(value, p, layouts, checks...), name, usage)
+}
+
+// PositionalTimeLayouts defines a positional [time.Time] parameter with the given name, default value, layouts, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Times are parsed by a [value.TimeParser] with the given layouts, which are tried in order,
+// the first also being used to display the default, and relative expressions such as now-2h.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalTimeLayouts(name string, value *time.Time, layouts []string, usage string, checks ...value.CheckFunc[time.Time]) *time.Time {
+	p := new(time.Time)
+	c.PositionalTimeLayoutsVar(p, name, value, layouts, usage, checks...)
+	return p
+}
+
+// PositionalDateVar defines a positional date parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Dates are parsed by a default [value.DateParser], accepting [time.DateOnly] and relative expressions such as yesterday.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalDateVar(p *time.Time, name string, value *time.Time, usage string, checks ...value.CheckFunc[time.Time]) {
+	c.PositionalVar(internal.NewDateValue(value, p, checks...), name, usage)
+}
+
+// PositionalDate defines a positional date parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Dates are parsed by a default [value.DateParser], accepting [time.DateOnly] and relative expressions such as yesterday.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalDate(name string, value *time.Time, usage string, checks ...value.CheckFunc[time.Time]) *time.Time {
+	p := new(time.Time)
+	c.PositionalDateVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalLocationVar defines a positional [time.Location] parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Locations are loaded by name, as by [time.LoadLocation].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalLocationVar(p **time.Location, name string, value **time.Location, usage string, checks ...value.CheckFunc[*time.Location]) {
+	c.PositionalVar(internal.NewLocationValue(value, p, checks...), name, usage)
+}
+
+// PositionalLocation defines a positional [time.Location] parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Locations are loaded by name, as by [time.LoadLocation].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalLocation(name string, value **time.Location, usage string, checks ...value.CheckFunc[*time.Location]) **time.Location {
+	p := new(*time.Location)
+	c.PositionalLocationVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalSecretVar defines a positional secret string parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Secrets are never displayed, either in usage output or in errors.
+// A value prefixed with @ names a file from which the secret is read, and - reads it from stdin,
+// or from the input of the shell when parsed within one. A literal leading @ is given as @@.
+//
+// If a required secret is missing and [Command.Terminal] is interactive, it is prompted for without echo,
+// and the secret typed is taken literally.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalSecretVar(p *string, name string, value *string, usage string, checks ...value.CheckFunc[string]) {
+	c.PositionalVar(internal.NewSecretValue(value, p, checks...), name, usage)
+}
+
+// PositionalSecret defines a positional secret string parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Secrets are never displayed, either in usage output or in errors.
+// A value prefixed with @ names a file from which the secret is read, and - reads it from stdin,
+// or from the input of the shell when parsed within one. A literal leading @ is given as @@.
+//
+// If a required secret is missing and [Command.Terminal] is interactive, it is prompted for without echo,
+// and the secret typed is taken literally.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalSecret(name string, value *string, usage string, checks ...value.CheckFunc[string]) *string {
+	p := new(string)
+	c.PositionalSecretVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalURLVar defines a positional [url.URL] parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// URLs are parsed as by [url.Parse], and may be restricted to given schemes using [github.com/michaeljpetter/command/check.Scheme].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalURLVar(p **url.URL, name string, value **url.URL, usage string, checks ...value.CheckFunc[*url.URL]) {
+	c.PositionalVar(internal.NewURLValue(value, p, checks...), name, usage)
+}
+
+// PositionalURL defines a positional [url.URL] parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// URLs are parsed as by [url.Parse], and may be restricted to given schemes using [github.com/michaeljpetter/command/check.Scheme].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalURL(name string, value **url.URL, usage string, checks ...value.CheckFunc[*url.URL]) **url.URL {
+	p := new(*url.URL)
+	c.PositionalURLVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalHostPortVar defines a positional host:port parameter with the given name, default value, default port, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If the port is omitted, it is filled from the given default port, which, if empty, requires a port to be given.
+// Hosts are not resolved.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalHostPortVar(p *string, name string, value *string, port string, usage string, checks ...value.CheckFunc[string]) {
+	c.PositionalVar(internal.NewHostPortValue(value, p, port, checks...), name, usage)
+}
+
+// PositionalHostPort defines a positional host:port parameter with the given name, default value, default port, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// If the port is omitted, it is filled from the given default port, which, if empty, requires a port to be given.
+// Hosts are not resolved.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalHostPort(name string, value *string, port string, usage string, checks ...value.CheckFunc[string]) *string {
+	p := new(string)
+	c.PositionalHostPortVar(p, name, value, port, usage, checks...)
+	return p
+}
+
+// PositionalAddrVar defines a positional [netip.Addr] parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Addresses are parsed as by [netip.ParseAddr].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalAddrVar(p *netip.Addr, name string, value *netip.Addr, usage string, checks ...value.CheckFunc[netip.Addr]) {
+	c.PositionalVar(internal.NewTextValue(value, p, checks...), name, usage)
+}
+
+// PositionalAddr defines a positional [netip.Addr] parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Addresses are parsed as by [netip.ParseAddr].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalAddr(name string, value *netip.Addr, usage string, checks ...value.CheckFunc[netip.Addr]) *netip.Addr {
+	p := new(netip.Addr)
+	c.PositionalAddrVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalPrefixVar defines a positional [netip.Prefix] parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Prefixes are parsed as by [netip.ParsePrefix].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalPrefixVar(p *netip.Prefix, name string, value *netip.Prefix, usage string, checks ...value.CheckFunc[netip.Prefix]) {
+	c.PositionalVar(internal.NewTextValue(value, p, checks...), name, usage)
+}
+
+// PositionalPrefix defines a positional [netip.Prefix] parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Prefixes are parsed as by [netip.ParsePrefix].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalPrefix(name string, value *netip.Prefix, usage string, checks ...value.CheckFunc[netip.Prefix]) *netip.Prefix {
+	p := new(netip.Prefix)
+	c.PositionalPrefixVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalAddrPortVar defines a positional [netip.AddrPort] parameter with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// Address and port pairs are parsed as by [netip.ParseAddrPort].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalAddrPortVar(p *netip.AddrPort, name string, value *netip.AddrPort, usage string, checks ...value.CheckFunc[netip.AddrPort]) {
+	c.PositionalVar(internal.NewTextValue(value, p, checks...), name, usage)
+}
+
+// PositionalAddrPort defines a positional [netip.AddrPort] parameter with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// Address and port pairs are parsed as by [netip.ParseAddrPort].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func (c *Command) PositionalAddrPort(name string, value *netip.AddrPort, usage string, checks ...value.CheckFunc[netip.AddrPort]) *netip.AddrPort {
+	p := new(netip.AddrPort)
+	c.PositionalAddrPortVar(p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalTextVar defines a positional parameter on c with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// The value is parsed and formatted via its [encoding.TextUnmarshaler] and [encoding.TextMarshaler] implementations.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalTextVar[T any, P value.TextPointer[T]](c *Command, p *T, name string, value *T, usage string, checks ...value.CheckFunc[T]) {
+	c.PositionalVar(internal.NewTextValue[T, P](value, p, checks...), name, usage)
+}
+
+// PositionalText defines a positional parameter on c with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// The value is parsed and formatted via its [encoding.TextUnmarshaler] and [encoding.TextMarshaler] implementations.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalText[T any, P value.TextPointer[T]](c *Command, name string, value *T, usage string, checks ...value.CheckFunc[T]) *T {
+	p := new(T)
+	PositionalTextVar[T, P](c, p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalFuncVar defines a positional parameter on c with the given name, default value, usage,
+// parse and format functions, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// If format is nil, the value is formatted as by [fmt.Sprint].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalFuncVar[T any](c *Command, p *T, name string, value *T, usage string, parse value.ParseFunc[T], format value.FormatFunc[T], checks ...value.CheckFunc[T]) {
+	c.PositionalVar(internal.NewFuncValue(value, p, parse, format, checks...), name, usage)
+}
+
+// PositionalFunc defines a positional parameter on c with the given name, default value, usage,
+// parse and format functions, and checks.
+// The returned pointer receives the parsed value.
+//
+// If format is nil, the value is formatted as by [fmt.Sprint].
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalFunc[T any](c *Command, name string, value *T, usage string, parse value.ParseFunc[T], format value.FormatFunc[T], checks ...value.CheckFunc[T]) *T {
+	p := new(T)
+	PositionalFuncVar(c, p, name, value, usage, parse, format, checks...)
+	return p
+}
+
+// PositionalEnumVar defines a positional parameter on c with the given name, default value, usage,
+// enumerated choices, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// The parameter accepts the names and aliases of the choices, which are listed in usage output,
+// and displays its default by name.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalEnumVar[T comparable](c *Command, p *T, name string, value *T, usage string, enum value.Enum[T], checks ...value.CheckFunc[T]) {
+	c.PositionalVar(internal.NewEnumValue(value, p, enum, checks...), name, usage)
+}
+
+// PositionalEnum defines a positional parameter on c with the given name, default value, usage,
+// enumerated choices, and checks.
+// The returned pointer receives the parsed value.
+//
+// The parameter accepts the names and aliases of the choices, which are listed in usage output,
+// and displays its default by name.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalEnum[T comparable](c *Command, name string, value *T, usage string, enum value.Enum[T], checks ...value.CheckFunc[T]) *T {
+	p := new(T)
+	PositionalEnumVar(c, p, name, value, usage, enum, checks...)
+	return p
+}
+
+// PositionalListVar defines a positional list parameter on c with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// The list is given as comma-separated elements, each of which is parsed as its individual positional
+// parameter would be, and must pass every check.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalListVar[T value.Scalar](c *Command, p *[]T, name string, value *[]T, usage string, checks ...value.CheckFunc[T]) {
+	c.PositionalVar(internal.NewListValue(value, p, checks...), name, usage)
+}
+
+// PositionalList defines a positional list parameter on c with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// The list is given as comma-separated elements, each of which is parsed as its individual positional
+// parameter would be, and must pass every check.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalList[T value.Scalar](c *Command, name string, value *[]T, usage string, checks ...value.CheckFunc[T]) *[]T {
+	p := new([]T)
+	PositionalListVar(c, p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalRangeVar defines a positional [value.Range] parameter on c with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// The range is given as lo-hi, lo..hi, or lo:hi, inclusive of both bounds, or lo..<hi, exclusive of the upper bound.
+// Either bound may be omitted, as in :hi or lo.., except when separated by a dash,
+// and a single value is a range containing only itself.
+// Each bound is parsed as its individual positional parameter would be.
+// For integers, every value in the range must pass every check, with an omitted bound extending
+// to the limit of the type, and a range of more than 1048576 values cannot be checked.
+// For floating-point numbers, whose values cannot be enumerated, only the bounds given are checked.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalRangeVar[T value.Number](c *Command, p *value.Range[T], name string, value *value.Range[T], usage string, checks ...value.CheckFunc[T]) {
+	c.PositionalVar(internal.NewRangeValue(value, p, checks...), name, usage)
+}
+
+// PositionalRange defines a positional [value.Range] parameter on c with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// The range is given as lo-hi, lo..hi, or lo:hi, inclusive of both bounds, or lo..<hi, exclusive of the upper bound.
+// Either bound may be omitted, as in :hi or lo.., except when separated by a dash,
+// and a single value is a range containing only itself.
+// Each bound is parsed as its individual positional parameter would be.
+// For integers, every value in the range must pass every check, with an omitted bound extending
+// to the limit of the type, and a range of more than 1048576 values cannot be checked.
+// For floating-point numbers, whose values cannot be enumerated, only the bounds given are checked.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalRange[T value.Number](c *Command, name string, value *value.Range[T], usage string, checks ...value.CheckFunc[T]) *value.Range[T] {
+	p := newOf(value)
+	PositionalRangeVar(c, p, name, value, usage, checks...)
+	return p
+}
+
+// PositionalPairVar defines a positional [value.Pair] parameter on c with the given name, default value, usage, and checks.
+// The pointer p defines the location to receive the parsed value.
+//
+// The pair is given as key=value, where the key and value are each trimmed of surrounding space
+// and parsed as their individual positional parameters would be. The value, and the key
+// when of the same type, must pass every check.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalPairVar[K, V value.Scalar](c *Command, p *value.Pair[K, V], name string, value *value.Pair[K, V], usage string, checks ...value.CheckFunc[V]) {
+	c.PositionalVar(internal.NewPairValue(value, p, checks...), name, usage)
+}
+
+// PositionalPair defines a positional [value.Pair] parameter on c with the given name, default value, usage, and checks.
+// The returned pointer receives the parsed value.
+//
+// The pair is given as key=value, where the key and value are each trimmed of surrounding space
+// and parsed as their individual positional parameters would be. The value, and the key
+// when of the same type, must pass every check.
+//
+// If value is nil, the parameter will have no default and will be treated as required.
+func PositionalPair[K, V value.Scalar](c *Command, name string, value *value.Pair[K, V], usage string, checks ...value.CheckFunc[V]) *value.Pair[K, V] {
+	p := newOf(value)
+	PositionalPairVar(c, p, name, value, usage, checks...)
+	return p
+}
+
+// newOf allocates a zero value of the type to which a (possibly nil) pointer points,
+// for use where the type cannot be named because the value package is shadowed.
+func newOf[T any](*T) *T {
+	return new(T)
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/command/flag"
+	"io"
+	"strings"
+)
+
+// prompt prompts for a missing required positional parameter when the terminal is interactive,
+// and either prompting is enabled or the parameter is a secret.
+// It reports whether the parameter was set, or else an [*ArgumentError] if reading fails
+// before the end of input, or if setting it fails and prompting is not enabled.
+func (c *Command) prompt(positional *positional) (bool, error) {
+	secret, _ := positional.value.(flag.Secret)
+	isSecret := secret != nil && secret.IsSecret()
+
+	if !c.Prompt && !isSecret || c.Terminal == nil || !c.Terminal.IsTerminal() {
+		return false, nil
+	}
+
+	read := c.Terminal.ReadLine
+	if isSecret {
+		read = c.Terminal.ReadSecret
+	}
+
+	message := promptMessage(positional)
+	for {
+		raw, err := read(message)
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if err != nil {
+			return false, &ArgumentError{positional.name, err,
+				fmt.Sprintf("cannot read argument for <%s>: %v", positional.name, err)}
+		}
+
+		set := positional.value.Set
+		// A secret typed at the prompt is taken literally, rather than naming a file or stdin.
+		if literal, ok := positional.value.(interface{ SetLiteral(string) error }); ok {
+			set = literal.SetLiteral
+		}
+
+		err = set(raw)
+		if err == nil {
+			return true, nil
+		}
+
+		err = &ArgumentError{positional.name, err,
+			fmt.Sprintf("invalid value \"%s\" for argument %s: %v", flag.Display(positional.value, raw), positional.name, err)}
+		if !c.Prompt {
+			return false, err
+		}
+
+		message = err.Error() + "\n" + promptMessage(positional)
+	}
+}
+
+func promptMessage(positional *positional) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)", positional.name, positional.usage)
+
+	if chooser, ok := positional.value.(flag.Chooser); ok {
+		fmt.Fprintf(&b, " (one of: %s)", strings.Join(chooser.Choices(), ", "))
+	}
+
+	b.WriteString(": ")
+	return b.String()
+}
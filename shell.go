@@ -0,0 +1,402 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/command/flag"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Shell configures the interactive shell started by the built-in shell subcommand.
+type Shell struct {
+	// Prompt is displayed before each line, defaulting to the name of the command followed by "> ".
+	Prompt string
+
+	// In and Out are the input and output of the shell, defaulting to [os.Stdin] and [os.Stdout].
+	// They are also used by subcommands run from the shell, for their output and any prompts.
+	In  io.Reader
+	Out io.Writer
+
+	// Edit enables line editing, with history and tab completion, which is otherwise
+	// enabled only when In is a terminal. Input is then read a key at a time, and echoed to Out.
+	Edit bool
+
+	// HistorySize is the number of lines kept in history, defaulting to 500.
+	HistorySize int
+}
+
+const (
+	shellCommand = "shell"
+	shellUsage   = "start an interactive shell"
+)
+
+// SetShell enables a built-in shell subcommand on commands which define subcommands,
+// which reads lines from the user, running each as a subcommand, until "exit" or end of input.
+//
+// Each line is split into arguments by [Split], and run through the same subcommands and parsing
+// as any other arguments. Errors are printed without ending the shell, whatever the error handling
+// of the command, and a subcommand which fails where it would otherwise exit or panic is stopped
+// without running the remainder of its handler. The shell additionally understands "exit" and "quit", which end it, and "history",
+// which lists the lines entered, unless subcommands are defined by the same names.
+// When editing, completion is as by [Command.Complete].
+func (c *Command) SetShell(shell Shell) {
+	if shell.Prompt == "" {
+		shell.Prompt = c.Name() + "> "
+	}
+	if shell.In == nil {
+		shell.In = os.Stdin
+	}
+	if shell.Out == nil {
+		shell.Out = os.Stdout
+	}
+	if shell.HistorySize <= 0 {
+		shell.HistorySize = 500
+	}
+
+	c.shell = &shell
+}
+
+// session is the shell being run by a command, which is inherited by its subcommands.
+type session struct {
+	in       io.Reader
+	out      io.Writer
+	terminal Terminal
+}
+
+// newShell creates the built-in shell subcommand.
+func (c *Command) newShell() *Command {
+	child := c.newChild(shellCommand, shellUsage, flag.ContinueOnError)
+	child.SetOutput(c.Output())
+	return child
+}
+
+func (c *Command) parseShell(args []string) error {
+	if err := c.newShell().Parse(args); err != nil {
+		return reported{err}
+	}
+
+	return c.runShell()
+}
+
+func (c *Command) runShell() error {
+	s := c.shell
+
+	output, prev := c.Output(), c.session
+	c.SetOutput(s.Out)
+	c.session = &session{s.In, s.Out, NewTerminal(s.In, s.Out)}
+	defer func() {
+		c.SetOutput(output)
+		c.session = prev
+	}()
+
+	var history []string
+	for {
+		line, err := c.readShellLine(history)
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(s.Out)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		args, err := Split(line)
+		if err != nil {
+			fmt.Fprintln(s.Out, err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		history = append(history, line)
+		if len(history) > s.HistorySize {
+			history = slices.Delete(history, 0, len(history)-s.HistorySize)
+		}
+
+		if _, defined := c.subcommands[args[0]]; !defined {
+			switch args[0] {
+			case "exit", "quit":
+				return nil
+			case "history":
+				for i, line := range history {
+					fmt.Fprintf(s.Out, "%4d  %s\n", i+1, line)
+				}
+				continue
+			}
+		}
+
+		err = c.runLine(args)
+		if r, ok := err.(reported); ok {
+			err = r.error
+		} else if err != nil && err != flag.ErrHelp && err != ErrVersion {
+			fmt.Fprintln(s.Out, err)
+		}
+	}
+}
+
+// runLine runs the subcommand given by a line of the shell in its own goroutine,
+// so that a failure which aborts its handler ends only that goroutine.
+// A panic is raised again in the shell.
+func (c *Command) runLine(args []string) (err error) {
+	done := make(chan any)
+	go func() {
+		defer func() { done <- recover() }()
+		err = c.parseCommand(args)
+	}()
+
+	if r := <-done; r != nil {
+		panic(r)
+	}
+	return err
+}
+
+// readShellLine reads a line of the shell, editing it when enabled.
+func (c *Command) readShellLine(history []string) (string, error) {
+	s := c.shell
+
+	edit := s.Edit
+	if file, ok := s.In.(*os.File); ok {
+		if info, err := file.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			if restore, err := enableRaw(file.Fd()); err == nil {
+				defer restore()
+				edit = true
+			}
+		}
+	}
+
+	if !edit {
+		fmt.Fprint(s.Out, s.Prompt)
+		return readLine(s.In)
+	}
+
+	e := lineEditor{s.In, s.Out, s.Prompt, history, c.Complete}
+	return e.readLine()
+}
+
+// Split splits a line into arguments in the manner of a POSIX shell, though without any expansion.
+// Arguments are separated by unquoted whitespace. Within single quotes, every character is taken
+// literally. Within double quotes, a backslash escapes only $, `, ", \, or newline. Elsewhere,
+// a backslash escapes any character.
+func Split(line string) ([]string, error) {
+	args, _, err := split(line)
+	return args, err
+}
+
+// split splits a line as by [Split], additionally returning the offset at which the last
+// argument begins, or the length of the line if it ends between arguments.
+func split(line string) (args []string, start int, err error) {
+	var arg strings.Builder
+	var quote rune
+	escaped, inArg := false, false
+	start = len(line)
+
+	begin := func(i int) {
+		if !inArg {
+			inArg, start = true, i
+		}
+	}
+
+	for i, r := range line {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("$`\"\\\n", r) {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\\':
+			begin(i)
+			escaped = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			begin(i)
+			quote = r
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg, start = false, len(line)
+			}
+		default:
+			begin(i)
+			arg.WriteRune(r)
+		}
+	}
+
+	if escaped {
+		return nil, start, errors.New("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, start, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, start, nil
+}
+
+// quoteArg quotes an argument for a shell line, where needed.
+func quoteArg(arg string) string {
+	if arg != "" && !strings.ContainsFunc(arg, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`'"\$`+"`", r)
+	}) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// lineEditor reads a line a key at a time, echoing it, with history and tab completion.
+type lineEditor struct {
+	in       io.Reader
+	out      io.Writer
+	prompt   string
+	history  []string
+	complete func([]string) []string
+}
+
+const (
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyBackspace = 0x08
+	keyTab       = '\t'
+	keyCtrlU     = 0x15
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+func (e *lineEditor) readLine() (string, error) {
+	var line []byte
+	index, draft := len(e.history), ""
+
+	redraw := func() {
+		fmt.Fprintf(e.out, "\r\x1b[K%s%s", e.prompt, line)
+	}
+
+	fmt.Fprint(e.out, e.prompt)
+
+	buf := make([]byte, 1)
+	for {
+		n, err := e.in.Read(buf)
+		if n == 0 {
+			if err == nil {
+				continue
+			}
+			if errors.Is(err, io.EOF) && 0 < len(line) {
+				fmt.Fprintln(e.out)
+				return string(line), nil
+			}
+			return "", err
+		}
+
+		switch b := buf[0]; b {
+		case '\r', '\n':
+			fmt.Fprintln(e.out)
+			return string(line), nil
+		case keyCtrlC:
+			fmt.Fprintln(e.out, "^C")
+			return "", nil
+		case keyCtrlD:
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+		case keyBackspace, keyDelete:
+			if 0 < len(line) {
+				_, size := utf8.DecodeLastRune(line)
+				line = line[:len(line)-size]
+				redraw()
+			}
+		case keyCtrlU:
+			line = line[:0]
+			redraw()
+		case keyTab:
+			line = e.completeLine(line)
+			redraw()
+		case keyEscape:
+			seq := make([]byte, 2)
+			if _, err := io.ReadFull(e.in, seq); err != nil || seq[0] != '[' {
+				continue
+			}
+			switch {
+			case seq[1] == 'A' && 0 < index:
+				if index == len(e.history) {
+					draft = string(line)
+				}
+				index--
+				line = []byte(e.history[index])
+			case seq[1] == 'B' && index < len(e.history):
+				index++
+				if index == len(e.history) {
+					line = []byte(draft)
+				} else {
+					line = []byte(e.history[index])
+				}
+			}
+			redraw()
+		default:
+			if b < 0x20 {
+				continue
+			}
+			line = append(line, b)
+			e.out.Write(buf)
+		}
+	}
+}
+
+// completeLine completes the last argument of a line. A single candidate replaces the argument,
+// and otherwise the argument is extended to the longest common prefix of the candidates.
+// When it cannot be extended, the candidates are listed.
+func (e *lineEditor) completeLine(line []byte) []byte {
+	args, start, err := split(string(line))
+	if err != nil {
+		return line
+	}
+	if start == len(line) {
+		args = append(args, "")
+	}
+
+	candidates := e.complete(args)
+	last := args[len(args)-1]
+
+	switch {
+	case len(candidates) == 0:
+		return line
+	case len(candidates) == 1:
+		completed := quoteArg(candidates[0])
+		if !strings.HasSuffix(completed, "=") {
+			completed += " "
+		}
+		return append(line[:start:start], completed...)
+	}
+
+	prefix := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for !strings.HasPrefix(candidate, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	if len(last) < len(prefix) {
+		return append(line[:start:start], quoteArg(prefix)...)
+	}
+
+	fmt.Fprintf(e.out, "\n%s\n", strings.Join(candidates, "  "))
+	return line
+}
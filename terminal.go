@@ -0,0 +1,99 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Terminal provides the interactive input used to prompt for missing values.
+type Terminal interface {
+	// IsTerminal indicates whether input is interactive, so that prompting is possible.
+	IsTerminal() bool
+
+	// ReadLine displays a prompt, then reads a single line of input.
+	ReadLine(prompt string) (string, error)
+
+	// ReadSecret displays a prompt, then reads a single line of input without echoing it.
+	ReadSecret(prompt string) (string, error)
+}
+
+// StdTerminal returns a [Terminal] which reads from [os.Stdin],
+// and displays prompts to [os.Stderr].
+func StdTerminal() Terminal {
+	return NewTerminal(os.Stdin, os.Stderr)
+}
+
+// NewTerminal returns a [Terminal] which reads from in, and displays prompts to out.
+//
+// If in is an [os.File], the terminal is interactive only when the file is a character device,
+// and echo is disabled while reading secrets. Otherwise, the terminal is always interactive,
+// and secrets are read in the same way as any other line.
+func NewTerminal(in io.Reader, out io.Writer) Terminal {
+	return terminal{in, out}
+}
+
+type terminal struct {
+	in  io.Reader
+	out io.Writer
+}
+
+func (t terminal) IsTerminal() bool {
+	file, ok := t.in.(*os.File)
+	if !ok {
+		return true
+	}
+
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (t terminal) ReadLine(prompt string) (string, error) {
+	fmt.Fprint(t.out, prompt)
+	return readLine(t.in)
+}
+
+func (t terminal) ReadSecret(prompt string) (string, error) {
+	file, ok := t.in.(*os.File)
+	if !ok {
+		return t.ReadLine(prompt)
+	}
+
+	restore, err := disableEcho(file.Fd())
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	// The newline is not echoed, so is written once reading completes.
+	defer fmt.Fprintln(t.out)
+
+	return t.ReadLine(prompt)
+}
+
+// readLine reads a single line one byte at a time,
+// so that no input beyond the line is consumed.
+func readLine(r io.Reader) (string, error) {
+	var line strings.Builder
+	buf := make([]byte, 1)
+
+	for {
+		n, err := r.Read(buf)
+		if 0 < n {
+			if buf[0] == '\n' {
+				break
+			}
+			line.WriteByte(buf[0])
+		}
+		if errors.Is(err, io.EOF) && 0 < line.Len() {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimSuffix(line.String(), "\r"), nil
+}
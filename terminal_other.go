@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package command
+
+import (
+	"errors"
+)
+
+func disableEcho(uintptr) (func(), error) {
+	return nil, errors.New("cannot disable terminal echo on this platform")
+}
+
+func enableRaw(uintptr) (func(), error) {
+	return nil, errors.New("cannot enable raw terminal input on this platform")
+}
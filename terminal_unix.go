@@ -0,0 +1,46 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package command
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func disableEcho(fd uintptr) (func(), error) {
+	return setTermios(fd, func(noEcho *syscall.Termios) {
+		noEcho.Lflag &^= syscall.ECHO
+		noEcho.Lflag |= syscall.ICANON | syscall.ISIG
+		noEcho.Iflag |= syscall.ICRNL
+	})
+}
+
+// enableRaw disables echo and line buffering, so that input is read a key at a time,
+// and signals, so that keys such as Ctrl-C are read rather than raised.
+func enableRaw(fd uintptr) (func(), error) {
+	return setTermios(fd, func(raw *syscall.Termios) {
+		raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.ISIG
+		raw.Iflag |= syscall.ICRNL
+		raw.Cc[syscall.VMIN] = 1
+		raw.Cc[syscall.VTIME] = 0
+	})
+}
+
+// setTermios modifies the terminal state, returning a function which restores it.
+func setTermios(fd uintptr, modify func(*syscall.Termios)) (func(), error) {
+	var state syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&state))); errno != 0 {
+		return nil, errno
+	}
+
+	modified := state
+	modify(&modified)
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&modified))); errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(&state)))
+	}, nil
+}
@@ -0,0 +1,493 @@
+package command
+
+import (
+	"fmt"
+	"github.com/michaeljpetter/command/flag"
+	"github.com/michaeljpetter/command/internal"
+	"github.com/michaeljpetter/command/value"
+	"github.com/michaeljpetter/fp"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Description describes a [Command], as rendered by a usage template.
+type Description struct {
+	// Name is the full name of the command, including the names of any parent commands.
+	Name string
+
+	// Path lists the names of the commands leading to this one, starting from the root.
+	Path []string
+
+	// Usage is the usage string given when the command was created.
+	Usage string
+
+	// Flags describes each flag, sorted by name.
+	Flags []FlagDescription
+
+	// FlagErrors lists any problems found while determining the flag defaults.
+	FlagErrors []string
+
+	// Subcommands describes each subcommand, including any built-in subcommands, sorted by name.
+	Subcommands []SubcommandDescription
+
+	// Plugins describes each external subcommand found when plugins are enabled, sorted by name,
+	// with the path of its executable as its usage. As finding plugins depends on the host,
+	// they are described only when rendering usage, and never by [Command.Describe].
+	Plugins []SubcommandDescription
+
+	// Positional describes each positional parameter, in order.
+	Positional []PositionalDescription
+
+	// Examples lists the example invocations of the command, in order.
+	Examples []Example
+
+	// Sections lists any additional sections of the command help, in order.
+	Sections []Section
+
+	// Hidden indicates whether the command, or any command leading to it, is hidden by [Command.HideSubcommand].
+	Hidden bool
+}
+
+// Visible returns the description without any hidden flags or subcommands,
+// as it is shown in usage, completion, and documentation.
+func (d Description) Visible() Description {
+	d.Flags = hide(d.Flags, func(f FlagDescription) bool { return f.Hidden })
+	d.Subcommands = hide(d.Subcommands, func(s SubcommandDescription) bool { return s.Hidden })
+	return d
+}
+
+// hide returns the descriptions which are not hidden, or nil if there are none.
+func hide[D any](descriptions []D, hidden func(D) bool) []D {
+	var visible []D
+	for _, description := range descriptions {
+		if !hidden(description) {
+			visible = append(visible, description)
+		}
+	}
+	return visible
+}
+
+// FlagDescription describes a single flag.
+type FlagDescription struct {
+	// Name is the name of the flag, without any leading dashes.
+	Name string
+
+	// Type is the name of the flag value, as extracted by [flag.UnquoteUsage],
+	// which is empty for boolean flags.
+	Type string
+
+	// Usage is the usage string of the flag, with any back-quoted value name unquoted.
+	Usage string
+
+	// Default is the default value as displayed, or empty when it is the zero value.
+	Default string
+
+	// Required indicates whether the flag must be given.
+	Required bool
+
+	// ValueType is the Go type of the parsed value, if known.
+	ValueType string
+
+	// Choices lists the accepted values, if the value is a [flag.Chooser].
+	Choices []string
+
+	// Constraints lists the constraints of any described checks, if the value is a [flag.Constrained].
+	Constraints []value.Constraint
+
+	// Secret indicates whether the value is a [flag.Secret].
+	Secret bool
+
+	// Env is the environment variable documented for the flag by [flag.FlagSet.DocumentEnv], if any.
+	Env string
+
+	// Hidden indicates whether the flag is hidden by [flag.FlagSet.Hide].
+	Hidden bool
+}
+
+// Summary returns the usage string followed by any choices and constraints,
+// as displayed where the environment variable and default value are shown separately.
+func (f FlagDescription) Summary() string {
+	return f.Usage + describeChoices(f.Choices) + describeConstraints(f.Constraints)
+}
+
+// Description returns the summary followed by any environment variable and default value,
+// as displayed in the options block.
+func (f FlagDescription) Description() string {
+	return f.Summary() + flag.DescribeEnv(f.Env) + describeDefault(f.Default)
+}
+
+// Synopsis returns the name of the command followed by a summary of its arguments,
+// as shown in the first line of usage.
+func (d Description) Synopsis() string {
+	var b strings.Builder
+	b.WriteString(d.Name)
+
+	if d.Flags != nil {
+		b.WriteString(" [options]")
+	}
+
+	if d.Subcommands != nil {
+		b.WriteString(" <command>")
+	} else {
+		for _, positional := range d.Positional {
+			if positional.Required {
+				fmt.Fprintf(&b, " <%s>", positional.Name)
+			} else {
+				fmt.Fprintf(&b, " [%s]", positional.Name)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// SubcommandDescription describes a single subcommand.
+type SubcommandDescription struct {
+	Name  string
+	Usage string
+
+	// Builtin indicates whether the subcommand is built in, such as the help or version subcommand.
+	Builtin bool
+
+	// Hidden indicates whether the subcommand is hidden by [Command.HideSubcommand].
+	Hidden bool
+}
+
+// PositionalDescription describes a single positional parameter.
+type PositionalDescription struct {
+	// Name is the name of the positional parameter.
+	Name string
+
+	// Usage is the usage string of the positional parameter.
+	Usage string
+
+	// Default is the default value as displayed, which is only meaningful when not Required.
+	Default string
+
+	// Required indicates whether the positional parameter must be given.
+	Required bool
+
+	// ValueType is the Go type of the parsed value, if known.
+	ValueType string
+
+	// Choices lists the accepted values, if the value is a [flag.Chooser].
+	Choices []string
+
+	// Constraints lists the constraints of any described checks, if the value is a [flag.Constrained].
+	Constraints []value.Constraint
+
+	// Secret indicates whether the value is a [flag.Secret].
+	Secret bool
+}
+
+// Summary returns the usage string followed by any choices and constraints,
+// as displayed where the default value is shown separately.
+func (p PositionalDescription) Summary() string {
+	return p.Usage + describeChoices(p.Choices) + describeConstraints(p.Constraints)
+}
+
+// Description returns the summary followed by any default value,
+// as displayed in the arguments block.
+func (p PositionalDescription) Description() string {
+	description := p.Summary()
+	if !p.Required {
+		description += " (default " + p.Default + ")"
+	}
+	return description
+}
+
+func describeChoices(choices []string) string {
+	if choices == nil {
+		return ""
+	}
+	return " (one of: " + strings.Join(choices, ", ") + ")"
+}
+
+func describeConstraints(constraints []value.Constraint) string {
+	if description := value.DescribeConstraints(constraints); description != "" {
+		return " (" + description + ")"
+	}
+	return ""
+}
+
+func constraintsOf(v any) []value.Constraint {
+	if constrained, ok := v.(flag.Constrained); ok {
+		return constrained.Constraints()
+	}
+	return nil
+}
+
+func describeDefault(defValue string) string {
+	if defValue == "" {
+		return ""
+	}
+	return " (default " + defValue + ")"
+}
+
+func choicesOf(v any) []string {
+	if chooser, ok := v.(flag.Chooser); ok {
+		return chooser.Choices()
+	}
+	return nil
+}
+
+func isRequired(v any) bool {
+	value, ok := v.(Value)
+	return ok && value.Required()
+}
+
+// valueType returns the Go type of the value returned by Get, if the value is a [flag.Getter].
+func valueType(v any) string {
+	if getter, ok := v.(flag.Getter); ok {
+		return fmt.Sprintf("%T", getter.Get())
+	}
+	return ""
+}
+
+func isSecret(v any) bool {
+	secret, ok := v.(flag.Secret)
+	return ok && secret.IsSecret()
+}
+
+// Describe returns the [Description] of the command.
+func (c *Command) Describe() Description {
+	d := Description{
+		Name:   c.Name(),
+		Path:   strings.Split(c.Name(), " "),
+		Usage:  c.usage,
+		Hidden: c.hidden,
+	}
+
+	c.FlagSet.VisitAll(func(f *flag.Flag) {
+		name, usage := flag.UnquoteUsage(f)
+		defValue, err := flag.DefaultText(f)
+		if err != nil {
+			d.FlagErrors = append(d.FlagErrors, err.Error())
+		}
+
+		d.Flags = append(d.Flags, FlagDescription{
+			Name:        f.Name,
+			Type:        name,
+			Usage:       usage,
+			Default:     defValue,
+			Required:    isRequired(f.Value),
+			ValueType:   valueType(f.Value),
+			Choices:     choicesOf(f.Value),
+			Constraints: constraintsOf(f.Value),
+			Secret:      isSecret(f.Value),
+			Env:         c.Env(f.Name),
+			Hidden:      c.Hidden(f.Name),
+		})
+	})
+
+	if c.HasSubcommands() {
+		usages := c.subcommandUsages()
+		for _, name := range slices.Sorted(maps.Keys(usages)) {
+			subcommand, defined := c.subcommands[name]
+			d.Subcommands = append(d.Subcommands, SubcommandDescription{
+				Name:    name,
+				Usage:   usages[name],
+				Builtin: !defined,
+				Hidden:  subcommand.hidden,
+			})
+		}
+	}
+
+	for _, positional := range c.positional {
+		d.Positional = append(d.Positional, PositionalDescription{
+			Name:        positional.name,
+			Usage:       positional.usage,
+			Default:     positional.defValue,
+			Required:    positional.value.Required(),
+			ValueType:   valueType(positional.value),
+			Choices:     choicesOf(positional.value),
+			Constraints: constraintsOf(positional.value),
+			Secret:      isSecret(positional.value),
+		})
+	}
+
+	d.Examples = slices.Clone(c.examples)
+	d.Sections = slices.Clone(c.sections)
+
+	return d
+}
+
+// UsageTemplate is the default template used to render the usage of every [Command]
+// which does not set its own. It is executed with a [Description], starting from
+// the template named "usage", which renders the sections named "synopsis", "description",
+// "options", "commands", "plugins", "arguments", "examples", and "sections" in turn.
+//
+// The "options" section lists the flags by rendering the template named "defaults",
+// which is also rendered alone by [Command.PrintDefaults].
+//
+// Any section may be replaced by parsing a new definition into a clone of the template,
+// such as:
+//
+//	t := template.Must(command.UsageTemplate.Clone())
+//	template.Must(t.Parse(`{{define "options"}}...{{end}}`))
+//
+// In addition to the standard functions, templates may call:
+//
+//	wrap indent text      the lines of text wrapped to the width of the command, less the indent
+//	join sep lines        the lines joined by sep
+//	longest names         the length of the longest name in a slice of descriptions, at least 4
+//	entry longest name text
+//	                      the name padded to longest, followed by its wrapped description
+var UsageTemplate = template.Must(template.New("usage").Funcs(usageFuncs(nil)).Parse(defaultUsageTemplate))
+
+const defaultUsageTemplate = `{{define "usage"}}` +
+	`{{template "synopsis" .}}` +
+	`{{template "description" .}}` +
+	`{{template "options" .}}` +
+	`{{template "commands" .}}` +
+	`{{template "plugins" .}}` +
+	`{{template "arguments" .}}` +
+	`{{template "examples" .}}` +
+	`{{template "sections" .}}` +
+	`{{end}}` +
+
+	`{{define "synopsis"}}` +
+	`Usage: {{.Synopsis}}` + "\n\n" +
+	`{{end}}` +
+
+	`{{define "description"}}` +
+	`{{range wrap 2 .Usage}}  {{.}}` + "\n" + `{{end}}` +
+	`{{end}}` +
+
+	`{{define "options"}}` +
+	`{{if .Flags}}` + "\nOptions:\n" +
+	`{{template "defaults" .}}` +
+	`{{end}}` +
+	`{{end}}` +
+
+	`{{define "defaults"}}` +
+	`{{range .Flags}}{{template "flag" .}}{{end}}` +
+	`{{if .FlagErrors}}` + "\n" + `{{range .FlagErrors}}{{.}}` + "\n" + `{{end}}{{end}}` +
+	`{{end}}` +
+
+	`{{define "flag"}}` +
+	`  -{{.Name}}{{with .Type}} {{.}}{{end}}` +
+	`{{if and (eq (len .Name) 1) (not .Type)}}` + "\t" + `{{else}}` + "\n    \t" + `{{end}}` +
+	`{{join "\n    \t" (wrap 8 .Description)}}` + "\n" +
+	`{{end}}` +
+
+	`{{define "commands"}}` +
+	`{{if .Subcommands}}` + "\nCommands:\n" +
+	`{{$longest := longest .Subcommands}}` +
+	`{{range .Subcommands}}{{entry $longest .Name .Usage}}{{end}}` +
+	`{{end}}` +
+	`{{end}}` +
+
+	`{{define "plugins"}}` +
+	`{{if .Plugins}}` + "\nPlugins:\n" +
+	`{{$longest := longest .Plugins}}` +
+	`{{range .Plugins}}{{entry $longest .Name .Usage}}{{end}}` +
+	`{{end}}` +
+	`{{end}}` +
+
+	`{{define "arguments"}}` +
+	`{{if and .Positional (not .Subcommands)}}` + "\nArguments:\n" +
+	`{{$longest := longest .Positional}}` +
+	`{{range .Positional}}{{entry $longest .Name .Description}}{{end}}` +
+	`{{end}}` +
+	`{{end}}` +
+
+	`{{define "examples"}}` +
+	`{{if .Examples}}` + "\nExamples:\n" +
+	`{{range .Examples}}  {{.Command}}` + "\n" +
+	`{{with .Usage}}{{range wrap 4 .}}    {{.}}` + "\n" + `{{end}}{{end}}` +
+	`{{end}}` +
+	`{{end}}` +
+	`{{end}}` +
+
+	`{{define "sections"}}` +
+	`{{range .Sections}}` + "\n" + `{{.Title}}:` + "\n" +
+	`{{range wrap 2 .Text}}  {{.}}` + "\n" + `{{end}}` +
+	`{{end}}` +
+	`{{end}}`
+
+// usageFuncs returns the functions available to usage templates, wrapping to the width of the given command.
+func usageFuncs(c *Command) template.FuncMap {
+	width := func() int {
+		if c == nil {
+			return 0
+		}
+		return c.WrapWidth()
+	}
+
+	return template.FuncMap{
+		"wrap": func(indent int, text string) []string {
+			return internal.Wrap(text, width(), indent)
+		},
+		"join": func(sep string, lines []string) string {
+			return strings.Join(lines, sep)
+		},
+		"longest": func(descriptions any) (int, error) {
+			switch d := descriptions.(type) {
+			case []SubcommandDescription:
+				names := fp.Map(func(s SubcommandDescription) string { return s.Name })(slices.Values(d))
+				return fp.MaxOf(fp.StringLen, 4)(names), nil
+			case []PositionalDescription:
+				names := fp.Map(func(p PositionalDescription) string { return p.Name })(slices.Values(d))
+				return fp.MaxOf(fp.StringLen, 4)(names), nil
+			}
+			return 0, fmt.Errorf("cannot find longest name of %T", descriptions)
+		},
+		"entry": func(longest int, name, description string) string {
+			return formatEntry(width(), longest, name, description)
+		},
+	}
+}
+
+// formatEntry formats a name padded to the given length, followed by its description,
+// which is wrapped with continuation lines aligned under the start of the description.
+func formatEntry(width, longest int, name, description string) string {
+	if width <= 0 {
+		return fmt.Sprintf("  %-*s  %s\n", longest, name, description)
+	}
+
+	var b strings.Builder
+	indent := 2 + longest + 2
+	for i, line := range internal.Wrap(description, width, indent) {
+		if i == 0 {
+			fmt.Fprintf(&b, "  %-*s  %s\n", longest, name, line)
+		} else {
+			fmt.Fprintf(&b, "%*s%s\n", indent, "", line)
+		}
+	}
+	return b.String()
+}
+
+// RenderUsage renders the usage of the command, to standard error unless configured otherwise,
+// using its UsageTemplate, or the package [UsageTemplate] if unset.
+// This is the default behavior of Usage.
+func (c *Command) RenderUsage() error {
+	return c.render("usage")
+}
+
+// PrintDefaults prints the flags of the command, to standard error unless configured otherwise,
+// as they are listed in its usage, by rendering the template named "defaults".
+func (c *Command) PrintDefaults() {
+	if err := c.render("defaults"); err != nil {
+		fmt.Fprintln(c.Output(), err)
+	}
+}
+
+// render executes the named template from the usage template of the command with its [Description].
+func (c *Command) render(name string) error {
+	tmpl := c.UsageTemplate
+	if tmpl == nil {
+		tmpl = UsageTemplate
+	}
+
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return err
+	}
+
+	d := c.Describe().Visible()
+	d.Plugins = c.describePlugins()
+	return tmpl.Funcs(usageFuncs(c)).ExecuteTemplate(c.Output(), name, d)
+}
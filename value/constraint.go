@@ -0,0 +1,90 @@
+package value
+
+import (
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Constraint describes the restriction applied by a check, so that it can be
+// displayed in usage output and documentation.
+type Constraint struct {
+	// Description is a short human-readable description, such as "at least 1".
+	Description string
+
+	// Min and Max are the formatted bounds of the accepted values, if any,
+	// which are exclusive when indicated.
+	Min, Max                   string
+	MinExclusive, MaxExclusive bool
+
+	// Choices lists the accepted values, if the check accepts only a fixed set.
+	Choices []string
+}
+
+// described associates checks with their constraints. Each entry retains its check,
+// so that the address identifying it cannot be reused.
+var described sync.Map
+
+type describedCheck struct {
+	check       any
+	constraints []Constraint
+}
+
+// identity returns the address of the closure referenced by a func value,
+// which is unique to each closure, unlike the address of its code.
+func identity[T any](check CheckFunc[T]) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&check))
+}
+
+// Describe associates constraints with a check, and returns the check.
+// The constraints of a check can then be found by [Constraints].
+func Describe[T any](check CheckFunc[T], constraints ...Constraint) CheckFunc[T] {
+	if check != nil && 0 < len(constraints) {
+		described.Store(identity(check), describedCheck{check, constraints})
+	}
+	return check
+}
+
+// Constraints returns the constraints associated by [Describe] with each of the given checks, in order.
+// Checks without constraints are skipped.
+func Constraints[T any](checks ...CheckFunc[T]) []Constraint {
+	var constraints []Constraint
+	for _, check := range checks {
+		if check == nil {
+			continue
+		}
+		if d, ok := described.Load(identity(check)); ok {
+			constraints = append(constraints, d.(describedCheck).constraints...)
+		}
+	}
+	return constraints
+}
+
+// DescribeConstraints joins the descriptions of constraints for display, such as "at least 1, not blank".
+// A single inclusive minimum and maximum are combined into a range, such as "1..65535".
+func DescribeConstraints(constraints []Constraint) string {
+	minimum, maximum := -1, -1
+	for i, c := range constraints {
+		isMin := c.Min != "" && c.Max == "" && !c.MinExclusive
+		isMax := c.Max != "" && c.Min == "" && !c.MaxExclusive
+
+		if isMin && minimum < 0 {
+			minimum = i
+		} else if isMax && maximum < 0 {
+			maximum = i
+		}
+	}
+
+	descriptions := make([]string, 0, len(constraints))
+	for i, c := range constraints {
+		switch {
+		case 0 <= minimum && 0 <= maximum && i == min(minimum, maximum):
+			descriptions = append(descriptions, constraints[minimum].Min+".."+constraints[maximum].Max)
+		case 0 <= minimum && 0 <= maximum && i == max(minimum, maximum):
+		case c.Description != "":
+			descriptions = append(descriptions, c.Description)
+		}
+	}
+
+	return strings.Join(descriptions, ", ")
+}
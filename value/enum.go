@@ -0,0 +1,61 @@
+package value
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Choice names one of the values of an [Enum], along with any aliases by which it may also be given.
+type Choice[T any] struct {
+	Name    string
+	Value   T
+	Aliases []string
+}
+
+// Enum defines the named choices of an enumerated value.
+type Enum[T comparable] struct {
+	// Choices lists the named choices, in the order they are displayed.
+	Choices []Choice[T]
+
+	// FoldCase enables case-insensitive matching of names and aliases.
+	FoldCase bool
+}
+
+// Names returns the names of all choices, in order, excluding aliases.
+func (e Enum[T]) Names() []string {
+	names := make([]string, 0, len(e.Choices))
+	for _, choice := range e.Choices {
+		names = append(names, choice.Name)
+	}
+	return names
+}
+
+func (e Enum[T]) matches(choice Choice[T], raw string) bool {
+	return slices.ContainsFunc(append([]string{choice.Name}, choice.Aliases...), func(name string) bool {
+		return name == raw || e.FoldCase && strings.EqualFold(name, raw)
+	})
+}
+
+// Parse returns the value of the choice matching the raw name or alias.
+func (e Enum[T]) Parse(raw string) (T, error) {
+	for _, choice := range e.Choices {
+		if e.matches(choice, raw) {
+			return choice.Value, nil
+		}
+	}
+
+	var zero T
+	return zero, fmt.Errorf("must be one of %s", strings.Join(e.Names(), ", "))
+}
+
+// Format returns the name of the choice with the given value,
+// or formats it as by [fmt.Sprint] if there is none.
+func (e Enum[T]) Format(value T) string {
+	for _, choice := range e.Choices {
+		if choice.Value == value {
+			return choice.Name
+		}
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,67 @@
+package value
+
+import (
+	"fmt"
+)
+
+// Number constrains the basic numeric types.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Scalar constrains the basic types which may be parsed as
+// the components of structured values, such as lists and pairs.
+type Scalar interface {
+	Number | ~string
+}
+
+// Pair holds a key and value parsed from a key=value argument.
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// String formats the pair as key=value.
+func (p Pair[K, V]) String() string {
+	return fmt.Sprintf("%v=%v", p.Key, p.Value)
+}
+
+// Range holds the bounds of a numeric range, either of which may be absent.
+// The lower bound is always inclusive, while the upper bound is inclusive unless Exclusive is set.
+type Range[T Number] struct {
+	Lo, Hi       T
+	HasLo, HasHi bool
+	Exclusive    bool
+}
+
+// Contains indicates whether a value lies within the range.
+func (r Range[T]) Contains(value T) bool {
+	switch {
+	case r.HasLo && value < r.Lo:
+		return false
+	case r.HasHi && r.Exclusive:
+		return value < r.Hi
+	case r.HasHi:
+		return value <= r.Hi
+	}
+	return true
+}
+
+// String formats the range as lo..hi, or lo..<hi when the upper bound is exclusive,
+// omitting any absent bound.
+func (r Range[T]) String() string {
+	var lo, hi string
+	if r.HasLo {
+		lo = fmt.Sprint(r.Lo)
+	}
+	if r.HasHi {
+		hi = fmt.Sprint(r.Hi)
+	}
+
+	if r.Exclusive {
+		return lo + "..<" + hi
+	}
+	return lo + ".." + hi
+}
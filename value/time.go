@@ -0,0 +1,137 @@
+package value
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts lists the layouts accepted by a [TimeParser] when none are configured.
+var DefaultTimeLayouts = []string{time.RFC3339Nano, time.DateTime, "2006-01-02T15:04:05", time.DateOnly}
+
+var relativeTime = regexp.MustCompile(`^(?i:(now|today|yesterday|tomorrow))(?:\s*([+-])\s*(\S+))?$`)
+
+// TimeParser parses and formats [time.Time] values.
+//
+// In addition to its layouts, it accepts the relative expressions now, today, yesterday, and tomorrow,
+// optionally followed by a signed duration offset, such as now-2h or yesterday+9h30m.
+// The expressions today, yesterday, and tomorrow refer to midnight of the respective day.
+type TimeParser struct {
+	// Layouts lists the layouts tried in order when parsing, as by [time.ParseInLocation].
+	// The first is also used for formatting. If empty, [DefaultTimeLayouts] is used.
+	Layouts []string
+
+	// Location is used for layouts without a time zone, and for relative expressions.
+	// If nil, [time.Local] is used.
+	Location *time.Location
+
+	// Now provides the current time for relative expressions.
+	// If nil, [time.Now] is used.
+	Now func() time.Time
+}
+
+func (p TimeParser) layouts() []string {
+	if len(p.Layouts) == 0 {
+		return DefaultTimeLayouts
+	}
+	return p.Layouts
+}
+
+func (p TimeParser) location() *time.Location {
+	if p.Location == nil {
+		return time.Local
+	}
+	return p.Location
+}
+
+func (p TimeParser) now() time.Time {
+	if p.Now == nil {
+		return time.Now().In(p.location())
+	}
+	return p.Now().In(p.location())
+}
+
+// Parse parses a raw time, trying relative expressions followed by each layout.
+func (p TimeParser) Parse(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+
+	if match := relativeTime.FindStringSubmatch(raw); match != nil {
+		return p.parseRelative(match[1], match[2], match[3])
+	}
+
+	for _, layout := range p.layouts() {
+		if parsed, err := time.ParseInLocation(layout, raw, p.location()); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, errors.New("parse error")
+}
+
+func (p TimeParser) parseRelative(base, sign, offset string) (time.Time, error) {
+	now := p.now()
+	year, month, day := now.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	var parsed time.Time
+	switch strings.ToLower(base) {
+	case "now":
+		parsed = now
+	case "today":
+		parsed = midnight
+	case "yesterday":
+		parsed = midnight.AddDate(0, 0, -1)
+	case "tomorrow":
+		parsed = midnight.AddDate(0, 0, 1)
+	}
+
+	if offset == "" {
+		return parsed, nil
+	}
+
+	duration, err := time.ParseDuration(offset)
+	if err != nil {
+		return time.Time{}, errors.New("parse error")
+	}
+	if sign == "-" {
+		duration = -duration
+	}
+
+	return parsed.Add(duration), nil
+}
+
+// Format formats a time using the first of the parser's layouts.
+func (p TimeParser) Format(t time.Time) string {
+	return t.Format(p.layouts()[0])
+}
+
+// DateParser parses and formats [time.Time] values representing whole dates,
+// which are always truncated to midnight.
+//
+// In addition to [time.DateOnly], it accepts the relative expressions today, yesterday, and tomorrow.
+type DateParser struct {
+	// Location is used for the resulting dates, and for relative expressions.
+	// If nil, [time.Local] is used.
+	Location *time.Location
+
+	// Now provides the current time for relative expressions.
+	// If nil, [time.Now] is used.
+	Now func() time.Time
+}
+
+// Parse parses a raw date.
+func (p DateParser) Parse(raw string) (time.Time, error) {
+	parsed, err := TimeParser{[]string{time.DateOnly}, p.Location, p.Now}.Parse(raw)
+	if err != nil {
+		return parsed, err
+	}
+
+	year, month, day := parsed.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, parsed.Location()), nil
+}
+
+// Format formats a date as [time.DateOnly].
+func (p DateParser) Format(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
@@ -1,6 +1,29 @@
 // Package flag defines value types used by the command package.
 package value
 
+import (
+	"encoding"
+)
+
+// Redacted is displayed in place of the values of secrets.
+const Redacted = "********"
+
 // CheckFunc defines a function that checks a value and
 // returns an error when the value fails the check.
 type CheckFunc[T any] func(T) error
+
+// ParseFunc defines a function that parses a raw command line
+// argument into a value, returning an error when parsing fails.
+type ParseFunc[T any] func(string) (T, error)
+
+// FormatFunc defines a function that formats a value for display,
+// such as when printing defaults in usage output.
+type FormatFunc[T any] func(T) string
+
+// TextPointer constrains a pointer to T which can be both marshaled to
+// and unmarshaled from text, such as *[netip.Addr], *[time.Time], or *[big.Int].
+type TextPointer[T any] interface {
+	*T
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}
@@ -0,0 +1,191 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/michaeljpetter/command/flag"
+	"io"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"text/template"
+)
+
+// ErrVersion is the error returned when version information is requested,
+// via the -version flag or the version subcommand, after it has been printed.
+var ErrVersion = errors.New("version requested")
+
+// DefaultVersionTemplate is the template used to print version information
+// when no other template is given. It is executed with a [VersionInfo].
+const DefaultVersionTemplate = `{{.Name}} {{.Version}}` +
+	`{{with .Revision}} ({{.}}{{if $.Modified}}, modified{{end}}){{end}}` +
+	`{{with .Time}} built {{.}}{{end}}` +
+	`{{with .GoVersion}} {{.}}{{end}}` + "\n"
+
+// VersionInfo describes the version of a program.
+type VersionInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	Time      string `json:"time,omitempty"`
+	GoVersion string `json:"goVersion,omitempty"`
+}
+
+// BuildVersionInfo returns the version information embedded in the running binary,
+// as read by [debug.ReadBuildInfo]. The name is left empty.
+func BuildVersionInfo() VersionInfo {
+	info := VersionInfo{Version: "(unknown)"}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	if build.Main.Version != "" {
+		info.Version = build.Main.Version
+	}
+	info.GoVersion = build.GoVersion
+
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		case "vcs.time":
+			info.Time = setting.Value
+		}
+	}
+
+	return info
+}
+
+// Version configures the version information printed by a [Command].
+type Version struct {
+	// Info is the version information to print. When its Version is empty,
+	// it is read from the build information by [BuildVersionInfo],
+	// and when its Name is empty, the name of the command is used.
+	Info VersionInfo
+
+	// Template is the text/template used to print the version information,
+	// defaulting to [DefaultVersionTemplate].
+	Template string
+
+	// JSON prints the version information as JSON by default.
+	JSON bool
+
+	// Out is the writer to which version information is printed, defaulting to [os.Stdout].
+	// Within a shell, the output of the shell is used instead.
+	Out io.Writer
+}
+
+// versionValue is the value of the -version flag, which is a boolean flag that
+// additionally accepts "json" to request JSON output.
+type versionValue struct {
+	Version
+	requested bool
+	json      bool
+}
+
+func (v *versionValue) IsBoolFlag() bool {
+	return true
+}
+
+func (v *versionValue) Set(raw string) error {
+	if raw == "json" {
+		v.requested, v.json = true, true
+		return nil
+	}
+
+	requested, err := strconv.ParseBool(raw)
+	if err != nil {
+		return errors.New("parse error")
+	}
+
+	v.requested, v.json = requested, v.JSON
+	return nil
+}
+
+func (v *versionValue) String() string {
+	if v == nil || !v.requested {
+		return "false"
+	}
+	if v.json {
+		return "json"
+	}
+	return "true"
+}
+
+const (
+	versionCommand = "version"
+	versionUsage   = "show version information"
+)
+
+// SetVersion enables version information for the command, defining a -version flag
+// and, on commands which define subcommands, a built-in version subcommand.
+// Either prints the version information and reports [ErrVersion],
+// which exits with status 0 under [flag.ExitOnError].
+// The flag additionally accepts -version=json, and the subcommand -json, to print JSON.
+// Being boolean, the flag never consumes a following argument, so -version json prints text.
+func (c *Command) SetVersion(version Version) {
+	if version.Info.Version == "" {
+		name := version.Info.Name
+		version.Info = BuildVersionInfo()
+		version.Info.Name = name
+	}
+	if version.Info.Name == "" {
+		version.Info.Name = c.Name()
+	}
+	if version.Template == "" {
+		version.Template = DefaultVersionTemplate
+	}
+	if version.Out == nil {
+		version.Out = os.Stdout
+	}
+
+	c.version = &versionValue{Version: version}
+	c.FlagSet.Var(c.version, "version", "print version information and exit")
+}
+
+// newVersion creates the built-in version subcommand, returning it along with its -json flag.
+func (c *Command) newVersion() (*Command, *bool) {
+	child := c.newChild(versionCommand, versionUsage, flag.ContinueOnError)
+	child.SetOutput(c.Output())
+	return child, child.Bool("json", c.version.JSON, "print version information as JSON")
+}
+
+func (c *Command) parseVersion(args []string) error {
+	child, asJSON := c.newVersion()
+
+	if err := child.Parse(args); err != nil {
+		return reported{err}
+	}
+
+	return c.printVersion(*asJSON)
+}
+
+func (c *Command) printVersion(asJSON bool) error {
+	info, out := c.version.Info, c.version.Out
+	if c.session != nil {
+		out = c.session.out
+	}
+
+	if asJSON {
+		if err := json.NewEncoder(out).Encode(info); err != nil {
+			return err
+		}
+		return ErrVersion
+	}
+
+	tmpl, err := template.New(versionCommand).Parse(c.version.Template)
+	if err != nil {
+		return fmt.Errorf("invalid version template: %w", err)
+	}
+	if err := tmpl.Execute(out, info); err != nil {
+		return fmt.Errorf("invalid version template: %w", err)
+	}
+
+	return ErrVersion
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"github.com/michaeljpetter/command/flag"
+	"maps"
+	"slices"
+)
+
+// Walk calls visit for this command, then for each of its subcommands in order of name,
+// recursively, stopping at the first error returned.
+//
+// Subcommands are described as they are declared by [Command.Define], without calling their handlers,
+// so any subcommand defined only by a handler is described by its name and usage alone.
+// Built-in subcommands are not visited.
+func (c *Command) Walk(visit func(*Command) error) error {
+	if err := visit(c); err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.subcommands)) {
+		if err := c.describeSubcommand(name).Walk(visit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeSubcommand creates the named subcommand as it is declared, without calling its handler.
+func (c *Command) describeSubcommand(name string) *Command {
+	subcommand := c.subcommands[name]
+
+	child := c.newChild(name, subcommand.usage, c.ErrorHandling())
+	child.hidden = c.hidden || subcommand.hidden
+	if subcommand.define != nil {
+		subcommand.define(child)
+	}
+	return child
+}
+
+// describe creates the named subcommand as it is declared, or the named built-in subcommand
+// where it is enabled and not otherwise defined, without calling any handler.
+func (c *Command) describe(name string) (*Command, bool) {
+	if _, ok := c.subcommands[name]; ok {
+		return c.describeSubcommand(name), true
+	}
+
+	switch {
+	case c.Help && name == helpCommand:
+		child := c.newChild(helpCommand, helpUsage, flag.ContinueOnError)
+		child.SetOutput(c.Output())
+		return child, true
+	case c.version != nil && name == versionCommand:
+		child, _ := c.newVersion()
+		return child, true
+	case c.shell != nil && name == shellCommand:
+		return c.newShell(), true
+	}
+
+	return nil, false
+}